@@ -39,7 +39,6 @@ require (
 	github.com/BurntSushi/toml v1.2.1 // indirect
 	github.com/go-chi/chi/v5 v5.2.3
 	github.com/go-playground/validator/v10 v10.28.0
-	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
 	github.com/joho/godotenv v1.5.1 // indirect
 	github.com/minio/minio-go/v7 v7.0.97
 	golang.org/x/image v0.33.0
@@ -14,9 +14,20 @@ import (
 	"image-processor/internal/config"
 	image_h "image-processor/internal/http-server/handler/image"
 	"image-processor/internal/http-server/router"
+	"image-processor/internal/http-server/signature"
+	font_repo "image-processor/internal/repository/font/db/postgres"
 	minio_repo "image-processor/internal/repository/image/cloud/minio"
 	postgres_repo "image-processor/internal/repository/image/db/postgres"
+	preset_repo "image-processor/internal/repository/preset/db/postgres"
+	watermark_repo "image-processor/internal/repository/watermark/db/postgres"
+	font_uc "image-processor/internal/usecase/font"
 	image_uc "image-processor/internal/usecase/image"
+	preset_uc "image-processor/internal/usecase/preset"
+	"image-processor/internal/usecase/processor"
+	"image-processor/internal/usecase/processor/decoder"
+	"image-processor/internal/usecase/processor/encoder"
+	"image-processor/internal/usecase/processor/operations"
+	watermark_uc "image-processor/internal/usecase/watermark"
 
 	"github.com/wb-go/wbf/dbpg"
 	"github.com/wb-go/wbf/zlog"
@@ -48,13 +59,30 @@ func NewApp(cfg *config.Config, logger *zlog.Zerolog) (*App, error) {
 	}
 
 	imageRepo := postgres_repo.NewImagesRepository(db, retries)
+	presetRepo := preset_repo.NewPresetsRepository(db, retries)
+	watermarkRepo := watermark_repo.NewWatermarksRepository(db, retries)
+	fontRepo := font_repo.NewFontsRepository(db, retries)
 	producer := broker.Producer(kafka.NewProducerClient(cfg))
 
-	imageUsecase := image_uc.NewImageUsecase(imageRepo, fileRepo, producer, logger, retries)
-	imageHandler := image_h.NewImageHandler(imageUsecase, logger)
+	transformer := processor.NewImageProcessor(fileRepo, imageRepo, cfg.MetadataPolicy(), cfg.Processing.ResampleKernel, logger)
+
+	imageUsecase := image_uc.NewImageUsecase(imageRepo, fileRepo, presetRepo, producer, transformer, decoder.Default(), logger, retries)
+	signer := signature.NewSigner(cfg.Signing.Secret, cfg.Signing.TTL)
+	if !signer.Enabled() {
+		logger.Warn().Msg("URL_SIGNING_SECRET is not set, transform requests will be rejected")
+	}
+	if cfg.Signing.APIKey == "" {
+		logger.Warn().Msg("URL_SIGNING_API_KEY is not set, transform URL signing is disabled")
+	}
+	presetUsecase := preset_uc.NewPresetUsecase(presetRepo, logger)
+	watermarkUsecase := watermark_uc.NewWatermarkUsecase(watermarkRepo, fileRepo, decoder.Default(), logger)
+	fontUsecase := font_uc.NewFontUsecase(fontRepo, fileRepo, logger)
+	imageHandler := image_h.NewImageHandler(imageUsecase, presetUsecase, watermarkUsecase, fontUsecase, operations.Default(), encoder.Default(), decoder.Default(), signer, logger)
 
 	h := &router.Handler{
 		ImageHandler: imageHandler,
+		Signer:       signer,
+		APIKey:       cfg.Signing.APIKey,
 	}
 
 	mux := router.SetupRouter(h)
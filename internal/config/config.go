@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"image-processor/internal/domain"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/wb-go/wbf/retry"
@@ -49,6 +52,16 @@ type Config struct {
 	Worker struct {
 		Concurrency int `env:"WORKER_CONCURRENCY"`
 	}
+	Signing struct {
+		Secret string        `env:"URL_SIGNING_SECRET"`
+		TTL    time.Duration `env:"URL_SIGNING_TTL" env-default:"24h"`
+		APIKey string        `env:"URL_SIGNING_API_KEY"`
+	}
+	Processing struct {
+		StripMetadata  bool                  `env:"PROCESSING_STRIP_METADATA" env-default:"true"`
+		KeepMetadata   []string              `env:"PROCESSING_KEEP_METADATA" env-separator:","`
+		ResampleKernel domain.ResampleKernel `env:"PROCESSING_RESAMPLE_KERNEL" env-default:"catmullrom" validate:"oneof=nearest bilinear approx-bilinear catmullrom lanczos"`
+	}
 }
 
 func MustLoad() (*Config, error) {
@@ -61,6 +74,13 @@ func MustLoad() (*Config, error) {
 	if err := validate.Struct(cfg); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
+	for idx, group := range cfg.Processing.KeepMetadata {
+		group = strings.TrimSpace(group)
+		cfg.Processing.KeepMetadata[idx] = group
+		if !domain.IsValidMetadataGroup(group) {
+			return nil, fmt.Errorf("config validation failed: PROCESSING_KEEP_METADATA: unknown metadata group %q", group)
+		}
+	}
 	return &cfg, nil
 }
 
@@ -73,6 +93,14 @@ func (c *Config) MinIOEndpoint() string {
 	return c.MinIO.Endpoint
 }
 
+func (c *Config) MetadataPolicy() domain.MetadataPolicy {
+	strip := c.Processing.StripMetadata
+	return domain.MetadataPolicy{
+		Strip: &strip,
+		Keep:  c.Processing.KeepMetadata,
+	}
+}
+
 func (c *Config) DefaultRetryStrategy() retry.Strategy {
 	return retry.Strategy{
 		Attempts: c.Retries.Attempts,
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+type PNGCompression string
+
+const (
+	PNGCompressionDefault PNGCompression = "default"
+	PNGCompressionNone    PNGCompression = "none"
+	PNGCompressionSpeed   PNGCompression = "speed"
+	PNGCompressionBest    PNGCompression = "best"
+)
+
+type TIFFCompression string
+
+const (
+	TIFFCompressionDeflate TIFFCompression = "deflate"
+	TIFFCompressionNone    TIFFCompression = "none"
+)
+
+type GIFPalette string
+
+const (
+	GIFPaletteAdaptive GIFPalette = "adaptive"
+	GIFPalettePlan9    GIFPalette = "plan9"
+	GIFPaletteWebSafe  GIFPalette = "websafe"
+)
+
+type OutputOptions struct {
+	Format          ImageFormat     `json:"format,omitempty"`
+	Quality         int             `json:"quality,omitempty"`
+	PNGCompression  PNGCompression  `json:"png_compression,omitempty"`
+	TIFFCompression TIFFCompression `json:"tiff_compression,omitempty"`
+	GIFColors       int             `json:"gif_colors,omitempty"`
+	GIFPalette      GIFPalette      `json:"gif_palette,omitempty"`
+	GIFDither       *bool           `json:"gif_dither,omitempty"`
+	Frame           *int            `json:"frame,omitempty"`
+}
+
+func (o OutputOptions) IsZero() bool {
+	return o == OutputOptions{}
+}
+
+func (o OutputOptions) Validate() error {
+	var errs ParamErrors
+	if o.Quality != 0 {
+		errs.checkRange(ParamQuality, float64(o.Quality), 1, 100)
+	}
+	if o.GIFColors != 0 {
+		errs.checkRange(ParamGIFColors, float64(o.GIFColors), 2, 256)
+	}
+	if o.Frame != nil && *o.Frame < 0 {
+		errs.add(ParamFrame, "must be a non-negative frame index")
+	}
+	switch o.PNGCompression {
+	case "", PNGCompressionDefault, PNGCompressionNone, PNGCompressionSpeed, PNGCompressionBest:
+	default:
+		errs.add(ParamPNGCompression, "must be one of default, none, speed, best")
+	}
+	switch o.TIFFCompression {
+	case "", TIFFCompressionDeflate, TIFFCompressionNone:
+	default:
+		errs.add(ParamTIFFCompression, "must be one of deflate, none")
+	}
+	switch o.GIFPalette {
+	case "", GIFPaletteAdaptive, GIFPalettePlan9, GIFPaletteWebSafe:
+	default:
+		errs.add(ParamGIFPalette, "must be one of adaptive, plan9, websafe")
+	}
+	return errs.orNil()
+}
+
+func NormalizeFormat(format string) ImageFormat {
+	switch f := ImageFormat(strings.ToLower(strings.TrimPrefix(format, "."))); f {
+	case FormatJPG:
+		return FormatJPEG
+	case "tif":
+		return FormatTIFF
+	default:
+		return f
+	}
+}
+
+func DecodeOutputOptions(raw json.RawMessage) (OutputOptions, error) {
+	var opts OutputOptions
+	if len(bytes.TrimSpace(raw)) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return opts, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&opts); err != nil {
+		return opts, decodeError(err)
+	}
+	opts.Format = NormalizeFormat(string(opts.Format))
+	if err := opts.Validate(); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
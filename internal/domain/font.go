@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+type FontFormat string
+
+const (
+	FontTTF FontFormat = "ttf"
+	FontOTF FontFormat = "otf"
+)
+
+type FontAsset struct {
+	ID        string
+	Filename  string
+	Family    string
+	Format    FontFormat
+	Size      int64
+	Path      string
+	CreatedAt time.Time
+}
+
+const (
+	PathPrefixFont   = "fonts/"
+	MaxFontAssetSize = 10 << 20
+)
+
+func IsValidFontID(id string) bool {
+	return assetIDPattern.MatchString(id)
+}
+
+func FontAssetPath(id string) string {
+	return PathPrefixFont + id
+}
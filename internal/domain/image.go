@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type Image struct {
 	ID               string
@@ -10,21 +13,69 @@ type Image struct {
 	Status           ImageStatus
 	OriginalPath     string
 	Bucket           string
+	Metadata         ImageMetadata
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 }
 
+type ImageMetadata struct {
+	Width        int
+	Height       int
+	ColorModel   string
+	BitDepth     int
+	FrameCount   int
+	Orientation  int
+	BlurHash     string
+	ThumbHash    string
+	AverageColor string
+	Palette      []PaletteColor
+	Exif         *ExifMetadata
+	Report       *MetadataReport
+}
+
+type ExifMetadata struct {
+	CameraMake  string          `json:"camera_make,omitempty"`
+	CameraModel string          `json:"camera_model,omitempty"`
+	LensModel   string          `json:"lens_model,omitempty"`
+	Software    string          `json:"software,omitempty"`
+	TakenAt     *time.Time      `json:"taken_at,omitempty"`
+	GPS         *GPSCoordinates `json:"gps,omitempty"`
+}
+
+type GPSCoordinates struct {
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	Altitude  *float64 `json:"altitude,omitempty"`
+}
+
 type ProcessedImage struct {
-	ID         string
-	ImageID    string
-	Operation  OperationType
-	Parameters string
-	Path       string
-	Size       int64
-	MimeType   string
-	Format     ImageFormat
-	Status     string
-	CreatedAt  time.Time
+	ID            string
+	ImageID       string
+	Operation     OperationType
+	Parameters    string
+	Variant       string
+	Steps         string
+	CacheKey      string
+	Preset        string
+	PresetVersion int
+	CropBox       *CropBox
+	Path          string
+	Size          int64
+	MimeType      string
+	Format        ImageFormat
+	Status        string
+	CreatedAt     time.Time
+}
+
+type CropBox struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+func (b CropBox) String() string {
+	return fmt.Sprintf("%d,%d,%d,%d", b.X, b.Y, b.Width, b.Height)
 }
 
 type ImageStatus string
@@ -40,13 +91,17 @@ const (
 type OperationType string
 
 const (
-	OpResize    OperationType = "resize"
-	OpThumbnail OperationType = "thumbnail"
-	OpWatermark OperationType = "watermark"
-	OpCrop      OperationType = "crop"
-	OpRotate    OperationType = "rotate"
-	OpFlip      OperationType = "flip"
-	OpGrayscale OperationType = "grayscale"
+	OpResize     OperationType = "resize"
+	OpThumbnail  OperationType = "thumbnail"
+	OpWatermark  OperationType = "watermark"
+	OpCrop       OperationType = "crop"
+	OpRotate     OperationType = "rotate"
+	OpFlip       OperationType = "flip"
+	OpGrayscale  OperationType = "grayscale"
+	OpResponsive OperationType = "responsive"
+	OpAdjust     OperationType = "adjust"
+	OpFilter     OperationType = "filter"
+	OpRedact     OperationType = "redact"
 )
 
 type ImageFormat string
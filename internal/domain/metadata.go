@@ -0,0 +1,64 @@
+package domain
+
+const (
+	MetadataGPS         = "gps"
+	MetadataCamera      = "camera"
+	MetadataDateTime    = "datetime"
+	MetadataCopyright   = "copyright"
+	MetadataArtist      = "artist"
+	MetadataDescription = "description"
+	MetadataSoftware    = "software"
+	MetadataOrientation = "orientation"
+	MetadataOther       = "other"
+)
+
+var metadataGroups = map[string]bool{
+	MetadataGPS:         true,
+	MetadataCamera:      true,
+	MetadataDateTime:    true,
+	MetadataCopyright:   true,
+	MetadataArtist:      true,
+	MetadataDescription: true,
+	MetadataSoftware:    true,
+	MetadataOrientation: true,
+	MetadataOther:       true,
+}
+
+func IsValidMetadataGroup(group string) bool {
+	return metadataGroups[group]
+}
+
+type MetadataPolicy struct {
+	Strip         *bool
+	Keep          []string
+	StripOriginal bool
+}
+
+func (p MetadataPolicy) Resolve(defaults MetadataPolicy) MetadataPolicy {
+	if p.Strip == nil {
+		p.Strip = defaults.Strip
+		p.Keep = defaults.Keep
+	}
+	return p
+}
+
+func (p MetadataPolicy) Keeps(group string) bool {
+	if p.Strip == nil || !*p.Strip {
+		return true
+	}
+	for _, keep := range p.Keep {
+		if keep == group {
+			return true
+		}
+	}
+	return false
+}
+
+type MetadataReport struct {
+	Removed          []string `json:"removed"`
+	Kept             []string `json:"kept"`
+	OriginalScrubbed bool     `json:"original_scrubbed"`
+	OriginalRedacted bool     `json:"original_redacted,omitempty"`
+	OriginalSize     int64    `json:"original_size,omitempty"`
+	OriginalMimeType string   `json:"original_mime_type,omitempty"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+type Capabilities struct {
+	Chainable         bool
+	PreservesGIF      bool
+	ChangesDimensions bool
+	MultiOutput       bool
+}
+
+type ParamDescriptor struct {
+	Name    string
+	Type    string
+	Default interface{}
+}
+
+type OperationDescriptor struct {
+	Name         OperationType
+	Params       []ParamDescriptor
+	PathTemplate string
+	Capabilities Capabilities
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPaletteSize    = 5
+	DefaultColorDistance  = 20
+	MaxColorDistance      = 100
+	DefaultColorMinWeight = 0.05
+	paletteLabEpsilon     = 216.0 / 24389
+	paletteLabKappa       = 24389.0 / 27
+	paletteWhiteX         = 0.95047
+	paletteWhiteZ         = 1.08883
+)
+
+var hexColorPattern = regexp.MustCompile(`^#?([0-9a-fA-F]{6})$`)
+
+type PaletteColor struct {
+	Color  string     `json:"color"`
+	Weight float64    `json:"weight"`
+	Lab    [3]float64 `json:"lab"`
+}
+
+type ColorFilter struct {
+	Lab         [3]float64
+	MaxDistance float64
+	MinWeight   float64
+}
+
+func ParseHexColor(value string) (r, g, b uint8, err error) {
+	match := hexColorPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, 0, 0, fmt.Errorf("invalid hex color %q", value)
+	}
+	rgb, err := strconv.ParseUint(match[1], 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid hex color %q: %w", value, err)
+	}
+	return uint8(rgb >> 16), uint8(rgb >> 8), uint8(rgb), nil
+}
+
+func HexColor(r, g, b uint8) string {
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+func LabFromRGB(r, g, b uint8) [3]float64 {
+	lr, lg, lb := srgbToLinear(r), srgbToLinear(g), srgbToLinear(b)
+	x := (0.4124564*lr + 0.3575761*lg + 0.1804375*lb) / paletteWhiteX
+	y := 0.2126729*lr + 0.7151522*lg + 0.0721750*lb
+	z := (0.0193339*lr + 0.1191920*lg + 0.9503041*lb) / paletteWhiteZ
+	fx, fy, fz := labF(x), labF(y), labF(z)
+	return [3]float64{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
+
+func LabDistance(a, b [3]float64) float64 {
+	return math.Sqrt((a[0]-b[0])*(a[0]-b[0]) + (a[1]-b[1])*(a[1]-b[1]) + (a[2]-b[2])*(a[2]-b[2]))
+}
+
+func srgbToLinear(value uint8) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func labF(t float64) float64 {
+	if t > paletteLabEpsilon {
+		return math.Cbrt(t)
+	}
+	return (paletteLabKappa*t + 16) / 116
+}
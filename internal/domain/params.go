@@ -0,0 +1,511 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Params interface {
+	Operation() OperationType
+	Validate() error
+}
+
+type ParamError struct {
+	Param   string
+	Message string
+}
+
+func (e ParamError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Param, e.Message)
+}
+
+type ParamErrors []ParamError
+
+func (e ParamErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, pe := range e {
+		msgs[i] = pe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+var ErrUnsupportedOperation = errors.New("unsupported operation type")
+
+type ResizeParams struct {
+	Width      int            `json:"width"`
+	Height     int            `json:"height"`
+	KeepAspect bool           `json:"keep_aspect"`
+	Fit        ResizeFit      `json:"fit,omitempty"`
+	Gravity    Gravity        `json:"gravity,omitempty"`
+	Background string         `json:"background,omitempty"`
+	NoUpscale  bool           `json:"no_upscale,omitempty"`
+	Kernel     ResampleKernel `json:"kernel,omitempty"`
+}
+
+func (p *ResizeParams) Operation() OperationType { return OpResize }
+
+func (p *ResizeParams) Validate() error {
+	var errs ParamErrors
+	errs.checkRange(ParamWidth, float64(p.Width), 0, MaxDimension)
+	errs.checkRange(ParamHeight, float64(p.Height), 0, MaxDimension)
+	if p.Width == 0 && p.Height == 0 {
+		errs.add(ParamWidth, "width or height must be set")
+	}
+	errs.checkFit(p.Fit, p.Gravity, p.Background)
+	errs.checkKernel(p.Kernel)
+	return errs.orNil()
+}
+
+type ThumbnailParams struct {
+	Size       int            `json:"size"`
+	CropToFit  bool           `json:"crop_to_fit"`
+	Fit        ResizeFit      `json:"fit,omitempty"`
+	Gravity    Gravity        `json:"gravity,omitempty"`
+	Background string         `json:"background,omitempty"`
+	NoUpscale  bool           `json:"no_upscale,omitempty"`
+	Kernel     ResampleKernel `json:"kernel,omitempty"`
+}
+
+func (p *ThumbnailParams) Operation() OperationType { return OpThumbnail }
+
+func (p *ThumbnailParams) Validate() error {
+	var errs ParamErrors
+	errs.checkRange(ParamSize, float64(p.Size), 1, MaxThumbnailSize)
+	errs.checkFit(p.Fit, p.Gravity, p.Background)
+	errs.checkKernel(p.Kernel)
+	return errs.orNil()
+}
+
+type WatermarkParams struct {
+	Text         string            `json:"text"`
+	WatermarkID  string            `json:"watermark_id,omitempty"`
+	Opacity      float64           `json:"opacity"`
+	Position     WatermarkPosition `json:"position"`
+	FontSize     float64           `json:"font_size"`
+	FontColor    string            `json:"font_color"`
+	Scale        float64           `json:"scale,omitempty"`
+	Margin       int               `json:"margin"`
+	Tile         bool              `json:"tile,omitempty"`
+	Spacing      int               `json:"spacing,omitempty"`
+	Angle        float64           `json:"angle,omitempty"`
+	FontID       string            `json:"font_id,omitempty"`
+	StrokeWidth  int               `json:"stroke_width,omitempty"`
+	StrokeColor  string            `json:"stroke_color,omitempty"`
+	ShadowOffset int               `json:"shadow_offset,omitempty"`
+	ShadowColor  string            `json:"shadow_color,omitempty"`
+	LineSpacing  float64           `json:"line_spacing,omitempty"`
+}
+
+func (p *WatermarkParams) Operation() OperationType { return OpWatermark }
+
+func (p *WatermarkParams) Validate() error {
+	var errs ParamErrors
+	if p.WatermarkID != "" {
+		if !IsValidWatermarkID(p.WatermarkID) {
+			errs.add(ParamWatermarkID, "must be a watermark UUID")
+		}
+		errs.checkRange(ParamScale, p.Scale, 0.01, 1)
+	} else if p.Text == "" || len(p.Text) > MaxWatermarkTextLength {
+		errs.add(ParamText, fmt.Sprintf("must be between 1 and %d characters", MaxWatermarkTextLength))
+	}
+	errs.checkRange(ParamMargin, float64(p.Margin), 0, MaxDimension)
+	errs.checkRange(ParamSpacing, float64(p.Spacing), 0, MaxDimension)
+	errs.checkRange(ParamOpacity, p.Opacity, 0.01, 1)
+	errs.checkRange(ParamFontSize, p.FontSize, 1, 500)
+	switch p.Position {
+	case WatermarkTopLeft, WatermarkTopRight, WatermarkTopCenter,
+		WatermarkBottomLeft, WatermarkBottomRight, WatermarkBottomCenter, WatermarkCenter:
+	default:
+		errs.add(ParamPosition, "must be one of top-left, top-right, top-center, bottom-left, bottom-right, bottom-center, center")
+	}
+	errs.checkColor(ParamFontColor, p.FontColor)
+	errs.checkRange(ParamAngle, p.Angle, -360, 360)
+	if p.FontID != "" && !IsValidFontID(p.FontID) {
+		errs.add(ParamFontID, "must be a font UUID")
+	}
+	errs.checkRange(ParamStrokeWidth, float64(p.StrokeWidth), 0, MaxWatermarkStrokeWidth)
+	errs.checkRange(ParamShadowOffset, float64(p.ShadowOffset), 0, MaxWatermarkShadowOffset)
+	if p.StrokeColor != "" {
+		errs.checkColor(ParamStrokeColor, p.StrokeColor)
+	}
+	if p.ShadowColor != "" {
+		errs.checkColor(ParamShadowColor, p.ShadowColor)
+	}
+	if p.LineSpacing != 0 {
+		errs.checkRange(ParamLineSpacing, p.LineSpacing, 0.5, 3)
+	}
+	return errs.orNil()
+}
+
+type CropParams struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+func (p *CropParams) Operation() OperationType { return OpCrop }
+
+func (p *CropParams) Validate() error {
+	var errs ParamErrors
+	errs.checkRange(ParamX, float64(p.X), 0, MaxDimension)
+	errs.checkRange(ParamY, float64(p.Y), 0, MaxDimension)
+	errs.checkRange(ParamWidth, float64(p.Width), 1, MaxDimension)
+	errs.checkRange(ParamHeight, float64(p.Height), 1, MaxDimension)
+	return errs.orNil()
+}
+
+type RotateParams struct {
+	Angle      float64 `json:"angle"`
+	Background string  `json:"background"`
+}
+
+func (p *RotateParams) Operation() OperationType { return OpRotate }
+
+func (p *RotateParams) Validate() error {
+	var errs ParamErrors
+	errs.checkRange(ParamAngle, p.Angle, -360, 360)
+	errs.checkColor(ParamBackground, p.Background)
+	return errs.orNil()
+}
+
+type FlipParams struct {
+	Direction FlipDirection `json:"direction"`
+}
+
+func (p *FlipParams) Operation() OperationType { return OpFlip }
+
+func (p *FlipParams) Validate() error {
+	switch p.Direction {
+	case FlipHorizontal, FlipVertical, FlipBoth:
+		return nil
+	default:
+		return ParamErrors{{Param: ParamDirection, Message: "must be one of horizontal, vertical, both"}}
+	}
+}
+
+type GrayscaleParams struct{}
+
+func (p *GrayscaleParams) Operation() OperationType { return OpGrayscale }
+
+func (p *GrayscaleParams) Validate() error { return nil }
+
+type AdjustParams struct {
+	Brightness   float64      `json:"brightness"`
+	Contrast     float64      `json:"contrast"`
+	Saturation   float64      `json:"saturation"`
+	Gamma        float64      `json:"gamma"`
+	Hue          float64      `json:"hue"`
+	AutoLevels   bool         `json:"auto_levels"`
+	WhiteBalance WhiteBalance `json:"white_balance,omitempty"`
+}
+
+func (p *AdjustParams) Operation() OperationType { return OpAdjust }
+
+func (p *AdjustParams) Validate() error {
+	var errs ParamErrors
+	errs.checkRange(ParamBrightness, p.Brightness, -1, 1)
+	errs.checkRange(ParamContrast, p.Contrast, -1, 1)
+	errs.checkRange(ParamSaturation, p.Saturation, -1, 1)
+	errs.checkRange(ParamGamma, p.Gamma, MinGamma, MaxGamma)
+	errs.checkRange(ParamHue, p.Hue, -180, 180)
+	if !IsValidWhiteBalance(p.WhiteBalance) {
+		errs.add(ParamWhiteBalance, "must be one of gray-world, white-patch")
+	}
+	return errs.orNil()
+}
+
+type FilterParams struct {
+	Filter    ConvolutionFilter `json:"filter"`
+	Radius    float64           `json:"radius"`
+	Amount    float64           `json:"amount"`
+	Threshold int               `json:"threshold,omitempty"`
+	Matrix    []float64         `json:"matrix,omitempty"`
+	Divisor   float64           `json:"divisor,omitempty"`
+	Bias      float64           `json:"bias,omitempty"`
+}
+
+func (p *FilterParams) Operation() OperationType { return OpFilter }
+
+func (p *FilterParams) Validate() error {
+	var errs ParamErrors
+	switch p.Filter {
+	case FilterGaussianBlur, FilterUnsharp:
+		errs.checkRange(ParamRadius, p.Radius, 0.1, MaxFilterRadius)
+	case FilterBoxBlur:
+		errs.checkRange(ParamRadius, p.Radius, 1, MaxFilterRadius)
+		if p.Radius != math.Trunc(p.Radius) {
+			errs.add(ParamRadius, "must be an integer for box-blur")
+		}
+	case FilterCustom:
+		if !IsValidFilterMatrixSize(len(p.Matrix)) {
+			errs.add(ParamMatrix, "must be a square matrix of 9, 25, 49 or 81 weights")
+		}
+		for _, weight := range p.Matrix {
+			if math.IsNaN(weight) || math.Abs(weight) > MaxFilterWeight {
+				errs.add(ParamMatrix, fmt.Sprintf("weights must be between %d and %d", -MaxFilterWeight, MaxFilterWeight))
+				break
+			}
+		}
+		errs.checkRange(ParamDivisor, p.Divisor, -MaxFilterWeight*81, MaxFilterWeight*81)
+		errs.checkRange(ParamBias, p.Bias, -MaxFilterBias, MaxFilterBias)
+	}
+	if !IsValidFilter(p.Filter) {
+		errs.add(ParamFilter, "must be one of gaussian-blur, box-blur, sharpen, unsharp, edge, emboss, custom")
+	}
+	errs.checkRange(ParamAmount, p.Amount, 0, MaxFilterAmount)
+	errs.checkRange(ParamThreshold, float64(p.Threshold), 0, 255)
+	return errs.orNil()
+}
+
+type RedactRegion struct {
+	X      int      `json:"x,omitempty"`
+	Y      int      `json:"y,omitempty"`
+	Width  int      `json:"width,omitempty"`
+	Height int      `json:"height,omitempty"`
+	Points [][2]int `json:"points,omitempty"`
+}
+
+type RedactParams struct {
+	Regions         []RedactRegion `json:"regions"`
+	Mode            RedactMode     `json:"mode"`
+	BlockSize       int            `json:"block_size,omitempty"`
+	Radius          float64        `json:"radius,omitempty"`
+	Color           string         `json:"color,omitempty"`
+	ReplaceOriginal bool           `json:"replace_original,omitempty"`
+}
+
+func (p *RedactParams) Operation() OperationType { return OpRedact }
+
+func (p *RedactParams) Validate() error {
+	var errs ParamErrors
+	if len(p.Regions) == 0 || len(p.Regions) > MaxRedactRegions {
+		errs.add(ParamRegions, fmt.Sprintf("must contain between 1 and %d regions", MaxRedactRegions))
+	}
+	for i, region := range p.Regions {
+		field := func(name string) string {
+			return fmt.Sprintf("%s[%d].%s", ParamRegions, i, name)
+		}
+		if region.Points == nil {
+			errs.checkRange(field(ParamX), float64(region.X), 0, MaxDimension)
+			errs.checkRange(field(ParamY), float64(region.Y), 0, MaxDimension)
+			errs.checkRange(field(ParamWidth), float64(region.Width), 1, MaxDimension)
+			errs.checkRange(field(ParamHeight), float64(region.Height), 1, MaxDimension)
+			continue
+		}
+		if region.X != 0 || region.Y != 0 || region.Width != 0 || region.Height != 0 {
+			errs.add(field(ParamPoints), "cannot be combined with x, y, width and height")
+		}
+		if len(region.Points) < 3 || len(region.Points) > MaxRedactPolygonPoints {
+			errs.add(field(ParamPoints), fmt.Sprintf("must contain between 3 and %d points", MaxRedactPolygonPoints))
+		}
+		for _, point := range region.Points {
+			if point[0] < 0 || point[0] > MaxDimension || point[1] < 0 || point[1] > MaxDimension {
+				errs.add(field(ParamPoints), fmt.Sprintf("coordinates must be between 0 and %d", MaxDimension))
+				break
+			}
+		}
+	}
+	switch p.Mode {
+	case RedactPixelate:
+		errs.checkRange(ParamBlockSize, float64(p.BlockSize), MinRedactBlockSize, MaxRedactBlockSize)
+	case RedactBlur:
+		errs.checkRange(ParamRadius, p.Radius, 1, MaxFilterRadius)
+	case RedactFill:
+		errs.checkColor(ParamColor, p.Color)
+	}
+	if !IsValidRedactMode(p.Mode) {
+		errs.add(ParamMode, "must be one of pixelate, blur, fill")
+	}
+	return errs.orNil()
+}
+
+type ResponsiveParams struct {
+	Widths    []int     `json:"widths"`
+	Densities []float64 `json:"densities"`
+	BaseWidth int       `json:"base_width"`
+	Sizes     string    `json:"sizes"`
+}
+
+func (p *ResponsiveParams) Operation() OperationType { return OpResponsive }
+
+func (p *ResponsiveParams) Validate() error {
+	var errs ParamErrors
+	if len(p.Widths) == 0 && len(p.Densities) == 0 {
+		errs.add(ParamWidths, "widths or densities must be set")
+	}
+	if len(p.Widths)+len(p.Densities) > MaxResponsiveOutputs {
+		errs.add(ParamWidths, fmt.Sprintf("at most %d outputs are allowed", MaxResponsiveOutputs))
+	}
+	for idx, width := range p.Widths {
+		errs.checkRange(fmt.Sprintf("%s[%d]", ParamWidths, idx), float64(width), 1, MaxDimension)
+	}
+	for idx, density := range p.Densities {
+		errs.checkRange(fmt.Sprintf("%s[%d]", ParamDensities, idx), density, 1, MaxResponsiveDensity)
+	}
+	if len(p.Densities) > 0 {
+		errs.checkRange(ParamBaseWidth, float64(p.BaseWidth), 1, MaxDimension)
+	}
+	if len(p.Sizes) > MaxResponsiveSizesLength {
+		errs.add(ParamSizes, fmt.Sprintf("must be at most %d characters", MaxResponsiveSizesLength))
+	}
+	return errs.orNil()
+}
+
+var (
+	paramsMu       sync.RWMutex
+	paramFactories = make(map[OperationType]func() Params)
+)
+
+func RegisterParams(op OperationType, factory func() Params) {
+	paramsMu.Lock()
+	defer paramsMu.Unlock()
+	paramFactories[op] = factory
+}
+
+func NewParams(op OperationType) (Params, error) {
+	paramsMu.RLock()
+	factory, ok := paramFactories[op]
+	paramsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedOperation, op)
+	}
+	return factory(), nil
+}
+
+func DecodeParams(op OperationType, raw json.RawMessage) (Params, error) {
+	params, err := NewParams(op)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(raw)) > 0 && !bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(params); err != nil {
+			return nil, decodeError(err)
+		}
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func CanonicalParams(params Params) string {
+	if params == nil {
+		return ""
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func (o *OperationParams) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type       OperationType
+		Parameters json.RawMessage
+		Output     string
+		Encoding   json.RawMessage
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	params, err := DecodeParams(raw.Type, raw.Parameters)
+	if err != nil {
+		return fmt.Errorf("invalid parameters for %s: %w", raw.Type, err)
+	}
+	encoding, err := DecodeOutputOptions(raw.Encoding)
+	if err != nil {
+		return fmt.Errorf("invalid encoding options for %s: %w", raw.Type, err)
+	}
+	o.Type = raw.Type
+	o.Parameters = params
+	o.Output = raw.Output
+	o.Encoding = encoding
+	return nil
+}
+
+func decodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return ParamErrors{{Param: typeErr.Field, Message: "must be " + jsonKind(typeErr.Type.Kind().String())}}
+	}
+	msg := err.Error()
+	if strings.HasPrefix(msg, "json: unknown field ") {
+		return ParamErrors{{Param: strings.Trim(strings.TrimPrefix(msg, "json: unknown field "), `"`), Message: "unknown parameter"}}
+	}
+	return ParamErrors{{Param: "params", Message: msg}}
+}
+
+func jsonKind(kind string) string {
+	switch kind {
+	case "int", "int64":
+		return "an integer"
+	case "float64":
+		return "a number"
+	case "bool":
+		return "a boolean"
+	default:
+		return "a " + kind
+	}
+}
+
+func (e *ParamErrors) add(param, message string) {
+	*e = append(*e, ParamError{Param: param, Message: message})
+}
+
+func (e *ParamErrors) checkRange(param string, value, min, max float64) {
+	if math.IsNaN(value) || value < min || value > max {
+		e.add(param, fmt.Sprintf("must be between %v and %v", min, max))
+	}
+}
+
+func (e *ParamErrors) checkFit(fit ResizeFit, gravity Gravity, background string) {
+	if fit != "" && !IsValidFit(fit) {
+		e.add(ParamFit, "must be one of fill, inside, cover, contain, outside")
+	}
+	if gravity != "" && !IsValidGravity(gravity) {
+		e.add(ParamGravity, "must be one of center, north, northeast, east, southeast, south, southwest, west, northwest, smart")
+	}
+	if background != "" {
+		e.checkColor(ParamBackground, background)
+	}
+}
+
+func (e *ParamErrors) checkKernel(kernel ResampleKernel) {
+	if kernel != "" && !IsValidKernel(kernel) {
+		e.add(ParamKernel, "must be one of nearest, bilinear, approx-bilinear, catmullrom, lanczos")
+	}
+}
+
+func (e *ParamErrors) checkColor(param, value string) {
+	parts := strings.Split(strings.ReplaceAll(value, " ", ""), ",")
+	if len(parts) == 3 || len(parts) == 4 {
+		valid := true
+		for _, part := range parts {
+			c, err := strconv.Atoi(part)
+			if err != nil || c < 0 || c > 255 {
+				valid = false
+			}
+		}
+		if valid {
+			return
+		}
+	}
+	e.add(param, "must be a color in R,G,B[,A] format with components 0-255")
+}
+
+func (e ParamErrors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
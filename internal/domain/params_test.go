@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func failedParams(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var errs ParamErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ParamErrors, got %T: %v", err, err)
+	}
+	names := make([]string, len(errs))
+	for i, pe := range errs {
+		names[i] = pe.Param
+	}
+	return names
+}
+
+func TestParamsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+		want   []string
+	}{
+		{"resize width only", &ResizeParams{Width: 300}, nil},
+		{"resize cover with gravity", &ResizeParams{Width: 300, Height: 200, Fit: FitCover, Gravity: GravitySmart, Kernel: KernelLanczos}, nil},
+		{"resize without dimensions", &ResizeParams{}, []string{ParamWidth}},
+		{"resize too wide", &ResizeParams{Width: MaxDimension + 1, Height: 10}, []string{ParamWidth}},
+		{"resize negative height", &ResizeParams{Width: 10, Height: -1}, []string{ParamHeight}},
+		{"resize unknown fit", &ResizeParams{Width: 10, Fit: "stretch"}, []string{ParamFit}},
+		{"resize unknown gravity", &ResizeParams{Width: 10, Gravity: "up"}, []string{ParamGravity}},
+		{"resize bad background", &ResizeParams{Width: 10, Background: "256,0,0"}, []string{ParamBackground}},
+		{"resize unknown kernel", &ResizeParams{Width: 10, Kernel: "cubic"}, []string{ParamKernel}},
+		{"thumbnail", &ThumbnailParams{Size: 200}, nil},
+		{"thumbnail zero size", &ThumbnailParams{}, []string{ParamSize}},
+		{"thumbnail too large", &ThumbnailParams{Size: MaxThumbnailSize + 1}, []string{ParamSize}},
+		{"crop", &CropParams{X: 10, Y: 20, Width: 100, Height: 50}, nil},
+		{"crop empty area", &CropParams{Width: 0, Height: 0}, []string{ParamWidth, ParamHeight}},
+		{"crop negative origin", &CropParams{X: -1, Width: 10, Height: 10}, []string{ParamX}},
+		{"rotate", &RotateParams{Angle: 90, Background: DefaultRotateBackground}, nil},
+		{"rotate out of range", &RotateParams{Angle: 361, Background: "0,0,0"}, []string{ParamAngle}},
+		{"rotate NaN angle", &RotateParams{Angle: math.NaN(), Background: "0,0,0"}, []string{ParamAngle}},
+		{"rotate bad background", &RotateParams{Angle: 45, Background: "red"}, []string{ParamBackground}},
+		{"flip", &FlipParams{Direction: FlipBoth}, nil},
+		{"flip unknown direction", &FlipParams{Direction: "diagonal"}, []string{ParamDirection}},
+		{"grayscale", &GrayscaleParams{}, nil},
+		{"adjust", &AdjustParams{Brightness: 0.2, Gamma: DefaultGamma, WhiteBalance: WhiteBalanceGrayWorld}, nil},
+		{"adjust gamma zero", &AdjustParams{}, []string{ParamGamma}},
+		{"adjust out of range", &AdjustParams{Contrast: 2, Hue: 181, Gamma: 1}, []string{ParamContrast, ParamHue}},
+		{"adjust unknown white balance", &AdjustParams{Gamma: 1, WhiteBalance: "auto"}, []string{ParamWhiteBalance}},
+		{"filter gaussian", &FilterParams{Filter: FilterGaussianBlur, Radius: 2, Amount: 1}, nil},
+		{"filter box fractional radius", &FilterParams{Filter: FilterBoxBlur, Radius: 1.5, Amount: 1}, []string{ParamRadius}},
+		{"filter custom", &FilterParams{Filter: FilterCustom, Matrix: []float64{0, -1, 0, -1, 5, -1, 0, -1, 0}, Amount: 1}, nil},
+		{"filter custom bad matrix", &FilterParams{Filter: FilterCustom, Matrix: []float64{1, 1}, Amount: 1}, []string{ParamMatrix}},
+		{"filter unknown", &FilterParams{Filter: "median", Amount: 1}, []string{ParamFilter}},
+		{"watermark text", &WatermarkParams{Text: "(c)", Opacity: 0.5, Position: WatermarkBottomRight, FontSize: 36, FontColor: "255,255,255"}, nil},
+		{"watermark empty text", &WatermarkParams{Opacity: 0.5, Position: WatermarkBottomRight, FontSize: 36, FontColor: "255,255,255"}, []string{ParamText}},
+		{"watermark bad asset id", &WatermarkParams{WatermarkID: "../logo", Scale: 0.2, Opacity: 0.5, Position: WatermarkTopRight, FontSize: 36, FontColor: "0,0,0"}, []string{ParamWatermarkID}},
+		{"watermark bad position and opacity", &WatermarkParams{Text: "x", Position: "middle", FontSize: 36, FontColor: "0,0,0"}, []string{ParamOpacity, ParamPosition}},
+		{"redact fill rectangle", &RedactParams{Regions: []RedactRegion{{X: 1, Y: 1, Width: 10, Height: 10}}, Mode: RedactFill, Color: "0,0,0"}, nil},
+		{"redact polygon", &RedactParams{Regions: []RedactRegion{{Points: [][2]int{{0, 0}, {10, 0}, {5, 5}}}}, Mode: RedactPixelate, BlockSize: 16}, nil},
+		{"redact no regions", &RedactParams{Mode: RedactFill, Color: "0,0,0"}, []string{ParamRegions}},
+		{"redact polygon with rectangle", &RedactParams{Regions: []RedactRegion{{X: 1, Points: [][2]int{{0, 0}, {10, 0}, {5, 5}}}}, Mode: RedactFill, Color: "0,0,0"}, []string{"regions[0].points"}},
+		{"redact degenerate polygon", &RedactParams{Regions: []RedactRegion{{Points: [][2]int{{0, 0}, {10, 0}}}}, Mode: RedactFill, Color: "0,0,0"}, []string{"regions[0].points"}},
+		{"redact unknown mode", &RedactParams{Regions: []RedactRegion{{Width: 10, Height: 10}}, Mode: "erase"}, []string{ParamMode}},
+		{"responsive widths", &ResponsiveParams{Widths: []int{320, 640}}, nil},
+		{"responsive densities", &ResponsiveParams{Densities: []float64{1, 2}, BaseWidth: 400}, nil},
+		{"responsive empty", &ResponsiveParams{}, []string{ParamWidths}},
+		{"responsive densities without base", &ResponsiveParams{Densities: []float64{2}}, []string{ParamBaseWidth}},
+		{"responsive zero width", &ResponsiveParams{Widths: []int{0}}, []string{"widths[0]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := failedParams(t, tt.params.Validate())
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() failed params = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeParams(t *testing.T) {
+	RegisterParams(OpResize, func() Params { return &ResizeParams{KeepAspect: true} })
+	RegisterParams(OpRotate, func() Params { return &RotateParams{Background: DefaultRotateBackground} })
+	tests := []struct {
+		name    string
+		op      OperationType
+		raw     string
+		want    Params
+		failed  []string
+		wantErr error
+	}{
+		{"defaults are kept", OpResize, `{"width": 300}`, &ResizeParams{Width: 300, KeepAspect: true}, nil, nil},
+		{"explicit values override defaults", OpRotate, `{"angle": 90, "background": "0,0,0"}`, &RotateParams{Angle: 90, Background: "0,0,0"}, nil, nil},
+		{"null uses defaults", OpRotate, `null`, &RotateParams{Background: DefaultRotateBackground}, nil, nil},
+		{"empty uses defaults", OpRotate, ``, &RotateParams{Background: DefaultRotateBackground}, nil, nil},
+		{"unknown field", OpResize, `{"width": 300, "depth": 2}`, nil, []string{"depth"}, nil},
+		{"wrong type", OpResize, `{"width": "wide"}`, nil, []string{ParamWidth}, nil},
+		{"invalid after decode", OpResize, `{"keep_aspect": false}`, nil, []string{ParamWidth}, nil},
+		{"malformed json", OpResize, `{"width":`, nil, []string{"params"}, nil},
+		{"unknown operation", "sepia", `{}`, nil, nil, ErrUnsupportedOperation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeParams(tt.op, json.RawMessage(tt.raw))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("DecodeParams() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if failed := failedParams(t, err); !reflect.DeepEqual(failed, tt.failed) {
+				t.Fatalf("DecodeParams() failed params = %v, want %v", failed, tt.failed)
+			}
+			if !reflect.DeepEqual(got, tt.want) && tt.failed == nil {
+				t.Errorf("DecodeParams() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalParamsIsStable(t *testing.T) {
+	a := CanonicalParams(&ResizeParams{Width: 300, Height: 200, Fit: FitCover})
+	b := CanonicalParams(&ResizeParams{Fit: FitCover, Height: 200, Width: 300})
+	if a != b {
+		t.Errorf("CanonicalParams() differs for equal params: %s vs %s", a, b)
+	}
+	if CanonicalParams(nil) != "" {
+		t.Errorf("CanonicalParams(nil) = %q, want empty", CanonicalParams(nil))
+	}
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+type Preset struct {
+	Name        string
+	Description string
+	Pipeline    bool
+	Operations  []OperationParams
+	Version     int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+const MaxPresetDescriptionLength = 500
+
+var presetNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,99}$`)
+
+func IsValidPresetName(name string) bool {
+	return presetNamePattern.MatchString(name)
+}
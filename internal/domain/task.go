@@ -1,17 +1,37 @@
 package domain
 
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
 type ProcessingTask struct {
-	ID           string
-	ImageID      string
-	OriginalPath string
-	Bucket       string
-	Operations   []OperationParams
-	Format       ImageFormat
+	ID            string
+	ImageID       string
+	OriginalPath  string
+	Bucket        string
+	Operations    []OperationParams
+	Format        ImageFormat
+	Pipeline      bool
+	AutoOrient    bool
+	Metadata      MetadataPolicy
+	Preset        string
+	PresetVersion int
+}
+
+type ProcessingOptions struct {
+	Pipeline   bool
+	AutoOrient bool
+	Metadata   MetadataPolicy
+	Preset     string
 }
 
 type OperationParams struct {
 	Type       OperationType
-	Parameters map[string]interface{}
+	Parameters Params
+	Output     string
+	Encoding   OutputOptions
 }
 
 type ProcessingResult struct {
@@ -19,9 +39,24 @@ type ProcessingResult struct {
 	ImageID        string
 	Status         ImageStatus
 	ProcessedPaths map[string]string
+	Variants       []ProcessedVariant
+	Metadata       *ImageMetadata
 	Error          string
 }
 
+type ProcessedVariant struct {
+	Operation  OperationType
+	Variant    string
+	Parameters string
+	Steps      string
+	CacheKey   string
+	CropBox    *CropBox
+	Path       string
+	Size       int64
+	MimeType   string
+	Format     ImageFormat
+}
+
 type WatermarkPosition string
 
 const (
@@ -34,6 +69,160 @@ const (
 	WatermarkCenter       WatermarkPosition = "center"
 )
 
+type ResizeFit string
+
+const (
+	FitFill    ResizeFit = "fill"
+	FitInside  ResizeFit = "inside"
+	FitCover   ResizeFit = "cover"
+	FitContain ResizeFit = "contain"
+	FitOutside ResizeFit = "outside"
+)
+
+func IsValidFit(fit ResizeFit) bool {
+	switch fit {
+	case FitFill, FitInside, FitCover, FitContain, FitOutside:
+		return true
+	default:
+		return false
+	}
+}
+
+type ResampleKernel string
+
+const (
+	KernelNearest        ResampleKernel = "nearest"
+	KernelBilinear       ResampleKernel = "bilinear"
+	KernelApproxBilinear ResampleKernel = "approx-bilinear"
+	KernelCatmullRom     ResampleKernel = "catmullrom"
+	KernelLanczos        ResampleKernel = "lanczos"
+)
+
+func IsValidKernel(kernel ResampleKernel) bool {
+	switch kernel {
+	case KernelNearest, KernelBilinear, KernelApproxBilinear, KernelCatmullRom, KernelLanczos:
+		return true
+	default:
+		return false
+	}
+}
+
+type ConvolutionFilter string
+
+const (
+	FilterGaussianBlur ConvolutionFilter = "gaussian-blur"
+	FilterBoxBlur      ConvolutionFilter = "box-blur"
+	FilterSharpen      ConvolutionFilter = "sharpen"
+	FilterUnsharp      ConvolutionFilter = "unsharp"
+	FilterEdge         ConvolutionFilter = "edge"
+	FilterEmboss       ConvolutionFilter = "emboss"
+	FilterCustom       ConvolutionFilter = "custom"
+)
+
+func IsValidFilter(filter ConvolutionFilter) bool {
+	switch filter {
+	case FilterGaussianBlur, FilterBoxBlur, FilterSharpen, FilterUnsharp, FilterEdge, FilterEmboss, FilterCustom:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsValidFilterMatrixSize(size int) bool {
+	switch size {
+	case 3 * 3, 5 * 5, 7 * 7, 9 * 9:
+		return true
+	default:
+		return false
+	}
+}
+
+type RedactMode string
+
+const (
+	RedactPixelate RedactMode = "pixelate"
+	RedactBlur     RedactMode = "blur"
+	RedactFill     RedactMode = "fill"
+)
+
+func IsValidRedactMode(mode RedactMode) bool {
+	switch mode {
+	case RedactPixelate, RedactBlur, RedactFill:
+		return true
+	default:
+		return false
+	}
+}
+
+type WhiteBalance string
+
+const (
+	WhiteBalanceNone       WhiteBalance = ""
+	WhiteBalanceGrayWorld  WhiteBalance = "gray-world"
+	WhiteBalanceWhitePatch WhiteBalance = "white-patch"
+)
+
+func IsValidWhiteBalance(wb WhiteBalance) bool {
+	switch wb {
+	case WhiteBalanceNone, WhiteBalanceGrayWorld, WhiteBalanceWhitePatch:
+		return true
+	default:
+		return false
+	}
+}
+
+type Gravity string
+
+const (
+	GravityCenter    Gravity = "center"
+	GravityNorth     Gravity = "north"
+	GravityNorthEast Gravity = "northeast"
+	GravityEast      Gravity = "east"
+	GravitySouthEast Gravity = "southeast"
+	GravitySouth     Gravity = "south"
+	GravitySouthWest Gravity = "southwest"
+	GravityWest      Gravity = "west"
+	GravityNorthWest Gravity = "northwest"
+	GravitySmart     Gravity = "smart"
+)
+
+func (g Gravity) Anchor() (float64, float64) {
+	x, y := 0.5, 0.5
+	switch g {
+	case GravityNorth, GravityNorthEast, GravityNorthWest:
+		y = 0
+	case GravitySouth, GravitySouthEast, GravitySouthWest:
+		y = 1
+	}
+	switch g {
+	case GravityWest, GravityNorthWest, GravitySouthWest:
+		x = 0
+	case GravityEast, GravityNorthEast, GravitySouthEast:
+		x = 1
+	}
+	return x, y
+}
+
+func IsValidGravity(g Gravity) bool {
+	switch g {
+	case GravityCenter, GravityNorth, GravityNorthEast, GravityEast, GravitySouthEast,
+		GravitySouth, GravitySouthWest, GravityWest, GravityNorthWest, GravitySmart:
+		return true
+	default:
+		return false
+	}
+}
+
+type FlipDirection string
+
+const (
+	FlipHorizontal FlipDirection = "horizontal"
+	FlipVertical   FlipDirection = "vertical"
+	FlipBoth       FlipDirection = "both"
+)
+
+const DefaultPipelineOutput = "final"
+
 const (
 	KafkaTopicProcessing = "image-processing"
 	KafkaTopicResults    = "image-processed"
@@ -51,24 +240,126 @@ const (
 	PathPrefixThumbnail = "thumbnails/"
 )
 
+const RedactedOriginalSuffix = ".redacted"
+
+func RedactedOriginalPath(originalPath string, format ImageFormat) string {
+	base := strings.TrimSuffix(originalPath, path.Ext(originalPath))
+	return strings.TrimSuffix(base, RedactedOriginalSuffix) + RedactedOriginalSuffix + "." + string(format)
+}
+
+func IsRedactedOriginalPath(originalPath string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(originalPath, path.Ext(originalPath)), RedactedOriginalSuffix)
+}
+
 const (
 	DefaultMaxUploadSize    = 32 << 20
 	DefaultThumbnailSize    = 200
 	DefaultJPEGQuality      = 85
 	DefaultWatermarkText    = "© ImageProcessor"
 	DefaultWatermarkOpacity = 0.5
+	DefaultRotateBackground = "255,255,255,255"
+	DefaultFitBackground    = "255,255,255,255"
+
+	DefaultWatermarkFontSize    = 36
+	DefaultWatermarkFontColor   = "255,255,255"
+	DefaultWatermarkScale       = 0.2
+	DefaultWatermarkMargin      = 20
+	DefaultWatermarkStrokeColor = "0,0,0"
+	DefaultWatermarkShadowColor = "0,0,0,160"
+	DefaultWatermarkLineSpacing = 1.2
+	MaxWatermarkStrokeWidth     = 10
+	MaxWatermarkShadowOffset    = 50
+	DefaultGamma                = 1
+	MinGamma                    = 0.1
+	MaxGamma                    = 10
+	DefaultFilterRadius         = 2
+	DefaultFilterAmount         = 1
+	MaxFilterRadius             = 100
+	MaxFilterAmount             = 10
+	MaxFilterBias               = 255
+	MaxFilterWeight             = 1000
+	DefaultRedactBlockSize      = 16
+	DefaultRedactRadius         = 12
+	DefaultRedactColor          = "0,0,0"
+	MinRedactBlockSize          = 2
+	MaxRedactBlockSize          = 256
+	MaxRedactRegions            = 100
+	MaxRedactPolygonPoints      = 100
+	MaxDimension                = 10000
+	MaxThumbnailSize            = 2000
+	MaxWatermarkTextLength      = 200
+
+	DefaultResponsiveSizes   = "100vw"
+	MaxResponsiveOutputs     = 12
+	MaxResponsiveDensity     = 4
+	MaxResponsiveSizesLength = 500
 )
 
 const (
-	ParamWidth      = "width"
-	ParamHeight     = "height"
-	ParamSize       = "size"
-	ParamText       = "text"
-	ParamPosition   = "position"
-	ParamOpacity    = "opacity"
-	ParamFontSize   = "font_size"
-	ParamFontColor  = "font_color"
-	ParamKeepAspect = "keep_aspect"
-	ParamCropToFit  = "crop_to_fit"
-	ParamAngle      = "angle"
+	ParamWidth        = "width"
+	ParamHeight       = "height"
+	ParamSize         = "size"
+	ParamText         = "text"
+	ParamPosition     = "position"
+	ParamOpacity      = "opacity"
+	ParamFontSize     = "font_size"
+	ParamFontColor    = "font_color"
+	ParamKeepAspect   = "keep_aspect"
+	ParamCropToFit    = "crop_to_fit"
+	ParamAngle        = "angle"
+	ParamX            = "x"
+	ParamY            = "y"
+	ParamDirection    = "direction"
+	ParamBackground   = "background"
+	ParamFit          = "fit"
+	ParamGravity      = "gravity"
+	ParamNoUpscale    = "no_upscale"
+	ParamKernel       = "kernel"
+	ParamWatermarkID  = "watermark_id"
+	ParamScale        = "scale"
+	ParamMargin       = "margin"
+	ParamSpacing      = "spacing"
+	ParamFontID       = "font_id"
+	ParamStrokeWidth  = "stroke_width"
+	ParamStrokeColor  = "stroke_color"
+	ParamShadowOffset = "shadow_offset"
+	ParamShadowColor  = "shadow_color"
+	ParamLineSpacing  = "line_spacing"
+	ParamBrightness   = "brightness"
+	ParamContrast     = "contrast"
+	ParamSaturation   = "saturation"
+	ParamGamma        = "gamma"
+	ParamHue          = "hue"
+	ParamAutoLevels   = "auto_levels"
+	ParamWhiteBalance = "white_balance"
+	ParamFilter       = "filter"
+	ParamRadius       = "radius"
+	ParamAmount       = "amount"
+	ParamThreshold    = "threshold"
+	ParamMatrix       = "matrix"
+	ParamDivisor      = "divisor"
+	ParamBias         = "bias"
+	ParamRegions      = "regions"
+	ParamPoints       = "points"
+	ParamMode         = "mode"
+	ParamBlockSize    = "block_size"
+	ParamColor        = "color"
+	ParamWidths       = "widths"
+	ParamDensities    = "densities"
+	ParamBaseWidth    = "base_width"
+	ParamSizes        = "sizes"
+
+	ParamFormat          = "format"
+	ParamQuality         = "quality"
+	ParamPNGCompression  = "png_compression"
+	ParamTIFFCompression = "tiff_compression"
+	ParamGIFColors       = "gif_colors"
+	ParamGIFPalette      = "gif_palette"
+	ParamFrame           = "frame"
 )
+
+var variantNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,100}$`)
+
+func IsValidVariantName(name string) bool {
+	return variantNamePattern.MatchString(name)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "testing"
+
+func TestRedactedOriginalPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		format   ImageFormat
+		want     string
+		redacted bool
+	}{
+		{"original/2024/05/17/1715939400.webp", FormatJPEG, "original/2024/05/17/1715939400.redacted.jpeg", false},
+		{"original/2024/05/17/1715939400.png", FormatPNG, "original/2024/05/17/1715939400.redacted.png", false},
+		{"original/2024/05/17/1715939400.redacted.jpeg", FormatJPEG, "original/2024/05/17/1715939400.redacted.jpeg", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := IsRedactedOriginalPath(tt.path); got != tt.redacted {
+				t.Errorf("IsRedactedOriginalPath() = %v, want %v", got, tt.redacted)
+			}
+			got := RedactedOriginalPath(tt.path, tt.format)
+			if got != tt.want {
+				t.Errorf("RedactedOriginalPath() = %q, want %q", got, tt.want)
+			}
+			if !IsRedactedOriginalPath(got) {
+				t.Errorf("IsRedactedOriginalPath(%q) = false", got)
+			}
+		})
+	}
+}
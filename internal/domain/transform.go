@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const TransformCacheKeyLength = 32
+
+func TransformCacheKey(operations ...OperationParams) string {
+	parts := make([]string, len(operations))
+	for i, operation := range operations {
+		encoding, _ := json.Marshal(operation.Encoding)
+		parts[i] = string(operation.Type) + "|" + CanonicalParams(operation.Parameters) + "|" + string(encoding)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, ";")))
+	return hex.EncodeToString(sum[:])[:TransformCacheKeyLength]
+}
+
+func ReplacesOriginal(operations ...OperationParams) bool {
+	for _, operation := range operations {
+		if redact, ok := operation.Parameters.(*RedactParams); ok && redact.ReplaceOriginal {
+			return true
+		}
+	}
+	return false
+}
+
+var DefaultResponsiveWidths = []int{320, 640, 960, 1280, 1920}
+
+type ResponsiveOutput struct {
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Descriptor string `json:"descriptor"`
+	Sizes      string `json:"sizes,omitempty"`
+}
+
+func ResponsiveVariantName(name string, format ImageFormat) string {
+	return fmt.Sprintf("%s-%s-%s", OpResponsive, name, format)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "testing"
+
+func TestTransformCacheKey(t *testing.T) {
+	resize := OperationParams{Type: OpResize, Parameters: &ResizeParams{Width: 300, Height: 200, Fit: FitCover}}
+	variants := map[string]OperationParams{
+		"base":          resize,
+		"other width":   {Type: OpResize, Parameters: &ResizeParams{Width: 301, Height: 200, Fit: FitCover}},
+		"other fit":     {Type: OpResize, Parameters: &ResizeParams{Width: 300, Height: 200, Fit: FitContain}},
+		"gravity":       {Type: OpResize, Parameters: &ResizeParams{Width: 300, Height: 200, Fit: FitCover, Gravity: GravitySmart}},
+		"kernel":        {Type: OpResize, Parameters: &ResizeParams{Width: 300, Height: 200, Fit: FitCover, Kernel: KernelNearest}},
+		"format":        {Type: OpResize, Parameters: resize.Parameters, Encoding: OutputOptions{Format: FormatPNG}},
+		"quality":       {Type: OpResize, Parameters: resize.Parameters, Encoding: OutputOptions{Quality: 70}},
+		"other quality": {Type: OpResize, Parameters: resize.Parameters, Encoding: OutputOptions{Quality: 71}},
+		"thumbnail":     {Type: OpThumbnail, Parameters: &ThumbnailParams{Size: 300}},
+	}
+	seen := make(map[string]string)
+	for name, operation := range variants {
+		key := TransformCacheKey(operation)
+		if len(key) != TransformCacheKeyLength {
+			t.Errorf("%s: key length = %d, want %d", name, len(key), TransformCacheKeyLength)
+		}
+		if other, ok := seen[key]; ok {
+			t.Errorf("%s and %s share cache key %s", name, other, key)
+		}
+		seen[key] = name
+	}
+	same := OperationParams{Type: OpResize, Parameters: &ResizeParams{Fit: FitCover, Height: 200, Width: 300}}
+	if TransformCacheKey(resize) != TransformCacheKey(same) {
+		t.Error("equal operations produce different cache keys")
+	}
+	rotate := OperationParams{Type: OpRotate, Parameters: &RotateParams{Angle: 90, Background: DefaultRotateBackground}}
+	if TransformCacheKey(resize, rotate) == TransformCacheKey(rotate, resize) {
+		t.Error("operation order does not change the cache key")
+	}
+	if TransformCacheKey(resize, rotate) == TransformCacheKey(resize) {
+		t.Error("chained operation does not change the cache key")
+	}
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+type WatermarkAsset struct {
+	ID        string
+	Filename  string
+	MimeType  string
+	Format    ImageFormat
+	Width     int
+	Height    int
+	Size      int64
+	Path      string
+	CreatedAt time.Time
+}
+
+const (
+	PathPrefixWatermark    = "watermarks/"
+	MaxWatermarkAssetSize  = 5 << 20
+	MaxWatermarkAssetPixel = 4096
+)
+
+var assetIDPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+func IsValidWatermarkID(id string) bool {
+	return assetIDPattern.MatchString(id)
+}
+
+func WatermarkAssetPath(id string) string {
+	return PathPrefixWatermark + id
+}
@@ -3,14 +3,63 @@ package image
 import (
 	"context"
 	"io"
+	"net/url"
+	"time"
 
 	"image-processor/internal/domain"
 )
 
 type imageUsecase interface {
-	UploadImage(ctx context.Context, file io.Reader, filename, contentType string, fileSize int64, operations []domain.OperationParams) (*domain.Image, error)
-	GetImage(ctx context.Context, id, operation string) (*domain.Image, io.ReadCloser, error)
-	GetStatus(ctx context.Context, id string) (domain.ImageStatus, error)
+	UploadImage(ctx context.Context, file io.Reader, filename, contentType string, fileSize int64, operations []domain.OperationParams, opts domain.ProcessingOptions) (*domain.Image, error)
+	GetImage(ctx context.Context, id, operation, variant string) (*domain.Image, *domain.ProcessedImage, io.ReadCloser, error)
+	TransformImage(ctx context.Context, id string, operations []domain.OperationParams, preset string) (*domain.ProcessedImage, io.ReadCloser, error)
+	GetStatus(ctx context.Context, id string) (*domain.Image, error)
+	GetMetadata(ctx context.Context, id string) (*domain.Image, error)
+	GetSrcset(ctx context.Context, id string) (*domain.Image, []domain.ProcessedImage, error)
 	DeleteImage(ctx context.Context, id string) error
-	ListImages(ctx context.Context, limit, offset int) ([]domain.Image, error)
+	ListImages(ctx context.Context, limit, offset int, filter *domain.ColorFilter) ([]domain.Image, error)
+}
+
+type presetUsecase interface {
+	CreatePreset(ctx context.Context, preset *domain.Preset) error
+	GetPreset(ctx context.Context, name string) (*domain.Preset, error)
+	ListPresets(ctx context.Context) ([]domain.Preset, error)
+	UpdatePreset(ctx context.Context, preset *domain.Preset) error
+	DeletePreset(ctx context.Context, name string) error
+}
+
+type watermarkUsecase interface {
+	UploadWatermark(ctx context.Context, file io.Reader, filename string) (*domain.WatermarkAsset, error)
+	GetWatermark(ctx context.Context, id string) (*domain.WatermarkAsset, io.ReadCloser, error)
+	ListWatermarks(ctx context.Context) ([]domain.WatermarkAsset, error)
+	DeleteWatermark(ctx context.Context, id string) error
+}
+
+type fontUsecase interface {
+	UploadFont(ctx context.Context, file io.Reader, filename string) (*domain.FontAsset, error)
+	GetFont(ctx context.Context, id string) (*domain.FontAsset, io.ReadCloser, error)
+	ListFonts(ctx context.Context) ([]domain.FontAsset, error)
+	DeleteFont(ctx context.Context, id string) error
+}
+
+type urlSigner interface {
+	Enabled() bool
+	DefaultTTL() time.Duration
+	Sign(path string, query url.Values, expiresAt time.Time) string
+}
+
+type operationCatalog interface {
+	Describe(name domain.OperationType) (domain.OperationDescriptor, bool)
+	DescribeAll() []domain.OperationDescriptor
+}
+
+type formatCatalog interface {
+	CanEncode(format domain.ImageFormat) bool
+	Formats() []domain.ImageFormat
+}
+
+type decoderCatalog interface {
+	DetectFormat(header []byte) (domain.ImageFormat, string, bool)
+	Extensions() []string
+	IsAllowedExtension(ext string) bool
 }
@@ -1,6 +1,9 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type UploadResponse struct {
 	ID        string    `json:"id"`
@@ -11,27 +14,135 @@ type UploadResponse struct {
 }
 
 type StatusResponse struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	BlurHash  string `json:"blurhash,omitempty"`
+	ThumbHash string `json:"thumbhash,omitempty"`
 }
 
 type ErrorResponse struct {
-	Error   string `json:"error"`
+	Error   string       `json:"error"`
+	Message string       `json:"message"`
+	Details string       `json:"details,omitempty"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+}
+
+type OperationSpec struct {
+	Type     string          `json:"type"`
+	Params   json.RawMessage `json:"params,omitempty"`
+	Output   string          `json:"output,omitempty"`
+	Encoding json.RawMessage `json:"encoding,omitempty"`
+}
+
+type UploadJSONRequest struct {
+	URL           string          `json:"url,omitempty"`
+	Data          string          `json:"data,omitempty"`
+	Filename      string          `json:"filename,omitempty"`
+	Pipeline      bool            `json:"pipeline"`
+	Preset        string          `json:"preset,omitempty"`
+	AutoOrient    *bool           `json:"auto_orient,omitempty"`
+	StripMetadata *bool           `json:"strip_metadata,omitempty"`
+	KeepMetadata  []string        `json:"keep_metadata,omitempty"`
+	StripOriginal bool            `json:"strip_original,omitempty"`
+	Operations    []OperationSpec `json:"operations"`
 }
 
 type UploadRequest struct {
-	File          interface{} `form:"file" binding:"required"`
-	Thumbnail     bool        `form:"thumbnail"`
-	Resize        bool        `form:"resize"`
-	Watermark     bool        `form:"watermark"`
-	WatermarkText string      `form:"watermark_text"`
+	File               interface{} `form:"file" binding:"required"`
+	Thumbnail          bool        `form:"thumbnail"`
+	Resize             bool        `form:"resize"`
+	Adjust             bool        `form:"adjust"`
+	AdjustBrightness   float64     `form:"adjust_brightness"`
+	AdjustContrast     float64     `form:"adjust_contrast"`
+	AdjustSaturation   float64     `form:"adjust_saturation"`
+	AdjustGamma        float64     `form:"adjust_gamma"`
+	AdjustHue          float64     `form:"adjust_hue"`
+	AdjustAutoLevels   bool        `form:"adjust_auto_levels"`
+	AdjustWhiteBalance string      `form:"adjust_white_balance"`
+	Filter             bool        `form:"filter"`
+	FilterType         string      `form:"filter_type"`
+	FilterRadius       float64     `form:"filter_radius"`
+	FilterAmount       float64     `form:"filter_amount"`
+	FilterThreshold    int         `form:"filter_threshold"`
+	Watermark          bool        `form:"watermark"`
+	WatermarkText      string      `form:"watermark_text"`
+	WatermarkID        string      `form:"watermark_id"`
+	Crop               bool        `form:"crop"`
+	CropX              int         `form:"crop_x"`
+	CropY              int         `form:"crop_y"`
+	CropWidth          int         `form:"crop_width"`
+	CropHeight         int         `form:"crop_height"`
+	Rotate             bool        `form:"rotate"`
+	RotateAngle        float64     `form:"rotate_angle"`
+	RotateBackground   string      `form:"rotate_background"`
+	Flip               bool        `form:"flip"`
+	FlipDirection      string      `form:"flip_direction"`
+	Grayscale          bool        `form:"grayscale"`
+	Format             string      `form:"format"`
+	Quality            int         `form:"quality"`
+	Pipeline           bool        `form:"pipeline"`
+	Preset             string      `form:"preset"`
+	AutoOrient         bool        `form:"auto_orient"`
+	StripMetadata      string      `form:"strip_metadata"`
+	KeepMetadata       string      `form:"keep_metadata"`
+	StripOriginal      bool        `form:"strip_original"`
+	Operations         string      `form:"operations"`
 }
 
 type GetImageRequest struct {
 	ID        string `uri:"id" binding:"required"`
 	Operation string `form:"operation"`
+	Variant   string `form:"variant"`
+}
+
+type TransformRequest struct {
+	ID           string `uri:"id" binding:"required"`
+	Width        string `form:"w"`
+	Height       string `form:"h"`
+	Fit          string `form:"fit"`
+	Gravity      string `form:"g"`
+	Bg           string `form:"bg"`
+	NoUpscale    string `form:"no_upscale"`
+	Format       string `form:"fmt"`
+	Quality      string `form:"q"`
+	Brightness   string `form:"brightness"`
+	Contrast     string `form:"contrast"`
+	Saturation   string `form:"saturation"`
+	Gamma        string `form:"gamma"`
+	Hue          string `form:"hue"`
+	AutoLevels   string `form:"auto_levels"`
+	WhiteBalance string `form:"white_balance"`
+	Preset       string `form:"preset"`
+}
+
+type SignTransformRequest struct {
+	Width        int     `json:"w,omitempty"`
+	Height       int     `json:"h,omitempty"`
+	Fit          string  `json:"fit,omitempty"`
+	Gravity      string  `json:"g,omitempty"`
+	Bg           string  `json:"bg,omitempty"`
+	NoUpscale    bool    `json:"no_upscale,omitempty"`
+	Format       string  `json:"fmt,omitempty"`
+	Quality      int     `json:"q,omitempty"`
+	Brightness   float64 `json:"brightness,omitempty"`
+	Contrast     float64 `json:"contrast,omitempty"`
+	Saturation   float64 `json:"saturation,omitempty"`
+	Gamma        float64 `json:"gamma,omitempty"`
+	Hue          float64 `json:"hue,omitempty"`
+	AutoLevels   bool    `json:"auto_levels,omitempty"`
+	WhiteBalance string  `json:"white_balance,omitempty"`
+	Preset       string  `json:"preset,omitempty"`
+	TTLSeconds   int     `json:"ttl_seconds,omitempty"`
+}
+
+type SignedURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type StatusRequest struct {
@@ -43,9 +154,138 @@ type DeleteRequest struct {
 }
 
 type ImageResponse struct {
+	ID           string    `json:"id"`
+	Filename     string    `json:"filename"`
+	Size         int64     `json:"size"`
+	Status       string    `json:"status"`
+	Orientation  int       `json:"orientation,omitempty"`
+	BlurHash     string    `json:"blurhash,omitempty"`
+	ThumbHash    string    `json:"thumbhash,omitempty"`
+	AverageColor string    `json:"average_color,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type OperationResponse struct {
+	Name         string                   `json:"name"`
+	Params       []OperationParamResponse `json:"params"`
+	PathTemplate string                   `json:"path_template"`
+	Capabilities OperationCapabilities    `json:"capabilities"`
+}
+
+type OperationParamResponse struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Default interface{} `json:"default"`
+}
+
+type OperationCapabilities struct {
+	Chainable         bool `json:"chainable"`
+	PreservesGIF      bool `json:"preserves_gif"`
+	ChangesDimensions bool `json:"changes_dimensions"`
+	MultiOutput       bool `json:"multi_output"`
+}
+
+type PresetRequest struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Pipeline    bool            `json:"pipeline"`
+	Operations  []OperationSpec `json:"operations"`
+}
+
+type PresetResponse struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Pipeline    bool            `json:"pipeline"`
+	Version     int             `json:"version"`
+	Operations  []OperationSpec `json:"operations"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type MetadataResponse struct {
+	ID             string                  `json:"id"`
+	Filename       string                  `json:"filename"`
+	Size           int64                   `json:"size"`
+	MimeType       string                  `json:"mime_type"`
+	Status         string                  `json:"status"`
+	Width          int                     `json:"width"`
+	Height         int                     `json:"height"`
+	ColorModel     string                  `json:"color_model"`
+	BitDepth       int                     `json:"bit_depth"`
+	FrameCount     int                     `json:"frame_count"`
+	Orientation    int                     `json:"orientation,omitempty"`
+	AverageColor   string                  `json:"average_color,omitempty"`
+	Palette        []PaletteColorResponse  `json:"palette,omitempty"`
+	Exif           *ExifResponse           `json:"exif,omitempty"`
+	MetadataReport *MetadataReportResponse `json:"metadata_report,omitempty"`
+}
+
+type PaletteColorResponse struct {
+	Color  string  `json:"color"`
+	Weight float64 `json:"weight"`
+}
+
+type MetadataReportResponse struct {
+	Removed          []string `json:"removed"`
+	Kept             []string `json:"kept"`
+	OriginalScrubbed bool     `json:"original_scrubbed"`
+	OriginalRedacted bool     `json:"original_redacted,omitempty"`
+}
+
+type ExifResponse struct {
+	CameraMake  string       `json:"camera_make,omitempty"`
+	CameraModel string       `json:"camera_model,omitempty"`
+	LensModel   string       `json:"lens_model,omitempty"`
+	Software    string       `json:"software,omitempty"`
+	TakenAt     *time.Time   `json:"taken_at,omitempty"`
+	GPS         *GPSResponse `json:"gps,omitempty"`
+}
+
+type GPSResponse struct {
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	Altitude  *float64 `json:"altitude,omitempty"`
+}
+
+type SrcsetResponse struct {
+	ID      string                 `json:"id"`
+	Sizes   string                 `json:"sizes"`
+	Formats []SrcsetFormatResponse `json:"formats"`
+}
+
+type SrcsetFormatResponse struct {
+	Format        string            `json:"format"`
+	MimeType      string            `json:"mime_type"`
+	Src           string            `json:"src"`
+	Srcset        string            `json:"srcset,omitempty"`
+	DensitySrcset string            `json:"density_srcset,omitempty"`
+	Candidates    []SrcsetCandidate `json:"candidates"`
+}
+
+type SrcsetCandidate struct {
+	URL        string `json:"url"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Descriptor string `json:"descriptor"`
+	Size       int64  `json:"size"`
+}
+
+type WatermarkResponse struct {
 	ID        string    `json:"id"`
 	Filename  string    `json:"filename"`
+	MimeType  string    `json:"mime_type"`
+	Format    string    `json:"format"`
+	Width     int       `json:"width"`
+	Height    int       `json:"height"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FontResponse struct {
+	ID        string    `json:"id"`
+	Filename  string    `json:"filename"`
+	Family    string    `json:"family"`
+	Format    string    `json:"format"`
 	Size      int64     `json:"size"`
-	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -4,4 +4,8 @@ import "errors"
 
 var (
 	ErrInvalidFileFormat = errors.New("invalid file format")
+	ErrInvalidRemoteURL  = errors.New("invalid remote url")
+	ErrRemoteAddress     = errors.New("remote address is not allowed")
+	ErrRemoteFetch       = errors.New("failed to fetch remote image")
+	ErrInvalidBase64     = errors.New("invalid base64 payload")
 )
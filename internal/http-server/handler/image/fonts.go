@@ -0,0 +1,119 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/http-server/handler/image/dto"
+	font_uc "image-processor/internal/usecase/font"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var allowedFontExtensions = map[string]bool{
+	".ttf": true,
+	".otf": true,
+}
+
+func (h *ImageHandler) UploadFont(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxFontAssetSize+64<<10)
+	if err := r.ParseMultipartForm(domain.MaxFontAssetSize); err != nil {
+		h.logger.Warn().Err(err).Msg("Failed to parse font form")
+		h.respondError(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+	file, handler, err := r.FormFile("file")
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "File is required", nil)
+		return
+	}
+	defer file.Close()
+	if !allowedFontExtensions[strings.ToLower(filepath.Ext(handler.Filename))] {
+		h.respondError(w, http.StatusBadRequest, "Unsupported file format. Allowed: ttf, otf", nil)
+		return
+	}
+	asset, err := h.fonts.UploadFont(r.Context(), file, handler.Filename)
+	if err != nil {
+		h.handleFontError(w, err, "")
+		return
+	}
+	h.respondJSON(w, http.StatusCreated, fontResponse(asset))
+}
+
+func (h *ImageHandler) ListFonts(w http.ResponseWriter, r *http.Request) {
+	assets, err := h.fonts.ListFonts(r.Context())
+	if err != nil {
+		h.handleFontError(w, err, "")
+		return
+	}
+	response := make([]dto.FontResponse, len(assets))
+	for idx := range assets {
+		response[idx] = fontResponse(&assets[idx])
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+func (h *ImageHandler) GetFont(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !domain.IsValidFontID(id) {
+		h.respondError(w, http.StatusNotFound, "Font not found", nil)
+		return
+	}
+	asset, reader, err := h.fonts.GetFont(r.Context(), id)
+	if err != nil {
+		h.handleFontError(w, err, id)
+		return
+	}
+	defer reader.Close()
+	w.Header().Set("Content-Type", "font/"+string(asset.Format))
+	w.Header().Set("Content-Length", strconv.FormatInt(asset.Size, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", asset.Filename))
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	if _, err := io.Copy(w, reader); err != nil {
+		h.logger.Error().Err(err).Str("font_id", id).Msg("Failed to stream font")
+	}
+}
+
+func (h *ImageHandler) DeleteFont(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !domain.IsValidFontID(id) {
+		h.respondError(w, http.StatusNotFound, "Font not found", nil)
+		return
+	}
+	if err := h.fonts.DeleteFont(r.Context(), id); err != nil {
+		h.handleFontError(w, err, id)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func fontResponse(asset *domain.FontAsset) dto.FontResponse {
+	return dto.FontResponse{
+		ID:        asset.ID,
+		Filename:  asset.Filename,
+		Family:    asset.Family,
+		Format:    string(asset.Format),
+		Size:      asset.Size,
+		CreatedAt: asset.CreatedAt,
+	}
+}
+
+func (h *ImageHandler) handleFontError(w http.ResponseWriter, err error, id string) {
+	switch {
+	case errors.Is(err, font_uc.ErrFontNotFound):
+		h.respondError(w, http.StatusNotFound, "Font not found", nil)
+	case errors.Is(err, font_uc.ErrInvalidFileFormat):
+		h.respondError(w, http.StatusBadRequest, "Unsupported font format", nil)
+	case errors.Is(err, font_uc.ErrFileTooLarge):
+		h.respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Font is too large (max %d MB)", domain.MaxFontAssetSize>>20), nil)
+	default:
+		h.logger.Error().Err(err).Str("font_id", id).Msg("Font request failed")
+		h.respondError(w, http.StatusInternalServerError, "Failed to process font request", err)
+	}
+}
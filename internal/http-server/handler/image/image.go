@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -23,25 +24,55 @@ import (
 )
 
 const (
-	maxMemory = 32 << 20
+	maxMemory       = 32 << 20
+	maxJSONBodySize = domain.DefaultMaxUploadSize/3*4 + 64<<10
 )
 
+var operationFormFlags = []string{
+	string(domain.OpThumbnail),
+	string(domain.OpResize),
+	string(domain.OpAdjust),
+	string(domain.OpFilter),
+	string(domain.OpWatermark),
+	string(domain.OpCrop),
+	string(domain.OpRotate),
+	string(domain.OpFlip),
+	string(domain.OpGrayscale),
+}
+
 type ImageHandler struct {
-	usecase  imageUsecase
-	validate *validator.Validate
-	logger   *zlog.Zerolog
+	usecase    imageUsecase
+	presets    presetUsecase
+	watermarks watermarkUsecase
+	fonts      fontUsecase
+	catalog    operationCatalog
+	formats    formatCatalog
+	decoders   decoderCatalog
+	signer     urlSigner
+	validate   *validator.Validate
+	logger     *zlog.Zerolog
 }
 
-func NewImageHandler(usecase imageUsecase, logger *zlog.Zerolog) *ImageHandler {
+func NewImageHandler(usecase imageUsecase, presets presetUsecase, watermarks watermarkUsecase, fonts fontUsecase, catalog operationCatalog, formats formatCatalog, decoders decoderCatalog, signer urlSigner, logger *zlog.Zerolog) *ImageHandler {
 	return &ImageHandler{
-		usecase:  usecase,
-		validate: validator.New(),
-		logger:   logger,
+		usecase:    usecase,
+		presets:    presets,
+		watermarks: watermarks,
+		fonts:      fonts,
+		catalog:    catalog,
+		formats:    formats,
+		decoders:   decoders,
+		signer:     signer,
+		validate:   validator.New(),
+		logger:     logger,
 	}
 }
 
 func (h *ImageHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		h.uploadFromJSON(w, r)
+		return
+	}
 	r.Body = http.MaxBytesReader(w, r.Body, domain.DefaultMaxUploadSize)
 	if err := r.ParseMultipartForm(maxMemory); err != nil {
 		h.logger.Warn().Err(err).Msg("Failed to parse multipart form")
@@ -65,17 +96,154 @@ func (h *ImageHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
 		h.respondError(w, http.StatusInternalServerError, "Failed to read file", err)
 		return
 	}
-	operations := h.parseOperationsFromForm(r.Form)
+	opts := domain.ProcessingOptions{
+		Pipeline:   r.Form.Get("pipeline") == "true",
+		AutoOrient: r.Form.Get("auto_orient") != "false",
+		Preset:     r.Form.Get("preset"),
+	}
+	var keep []string
+	if raw := r.Form.Get("keep_metadata"); raw != "" {
+		for _, group := range strings.Split(raw, ",") {
+			keep = append(keep, strings.TrimSpace(group))
+		}
+	}
+	var strip *bool
+	switch r.Form.Get("strip_metadata") {
+	case "true":
+		strip = boolPtr(true)
+	case "false":
+		strip = boolPtr(false)
+	case "":
+	default:
+		h.respondValidationError(w, []dto.FieldError{{Field: "strip_metadata", Message: "must be true or false"}})
+		return
+	}
+	var fieldErrs []dto.FieldError
+	opts.Metadata, fieldErrs = metadataPolicy(strip, keep, r.Form.Get("strip_original") == "true")
+	if len(fieldErrs) > 0 {
+		h.respondValidationError(w, fieldErrs)
+		return
+	}
+	var operations []domain.OperationParams
+	if opts.Preset != "" {
+		fieldErrs := presetReferenceErrors(opts.Preset, r.Form.Get("operations") != "")
+		if hasOperationFormFields(r.Form) {
+			fieldErrs = append(fieldErrs, dto.FieldError{Field: "preset", Message: "preset cannot be combined with operation form fields"})
+		}
+		if len(fieldErrs) > 0 {
+			h.respondValidationError(w, fieldErrs)
+			return
+		}
+	} else if raw := r.Form.Get("operations"); raw != "" {
+		var specs []dto.OperationSpec
+		if err := json.Unmarshal([]byte(raw), &specs); err != nil {
+			h.respondError(w, http.StatusBadRequest, "Invalid operations JSON", err)
+			return
+		}
+		operations, fieldErrs = h.validateOperationSpecs(specs, opts.Pipeline)
+		if len(fieldErrs) > 0 {
+			h.respondValidationError(w, fieldErrs)
+			return
+		}
+	} else {
+		operations, err = h.parseOperationsFromForm(r.Form)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+	}
+	h.upload(w, r, fileBytes, handler.Filename, handler.Header.Get("Content-Type"), operations, opts)
+}
+
+func (h *ImageHandler) uploadFromJSON(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxJSONBodySize)
+	var req dto.UploadJSONRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		h.logger.Warn().Err(err).Msg("Failed to decode JSON upload request")
+		h.respondError(w, http.StatusBadRequest, "Invalid JSON body", err)
+		return
+	}
+	if (req.URL == "") == (req.Data == "") {
+		h.respondValidationError(w, []dto.FieldError{{Field: "url", Message: "exactly one of url or data is required"}})
+		return
+	}
+	if req.Preset != "" {
+		if fieldErrs := presetReferenceErrors(req.Preset, len(req.Operations) > 0); len(fieldErrs) > 0 {
+			h.respondValidationError(w, fieldErrs)
+			return
+		}
+	}
+	operations, fieldErrs := h.validateOperationSpecs(req.Operations, req.Pipeline)
+	if len(fieldErrs) > 0 {
+		h.respondValidationError(w, fieldErrs)
+		return
+	}
+	policy, fieldErrs := metadataPolicy(req.StripMetadata, req.KeepMetadata, req.StripOriginal)
+	if len(fieldErrs) > 0 {
+		h.respondValidationError(w, fieldErrs)
+		return
+	}
+	var (
+		fileBytes []byte
+		filename  = req.Filename
+		err       error
+	)
+	if req.URL != "" {
+		var remoteName string
+		fileBytes, remoteName, err = fetchRemoteImage(r.Context(), req.URL)
+		if err != nil {
+			h.logger.Warn().Err(err).Str("url", req.URL).Msg("Failed to fetch remote image")
+			h.respondError(w, http.StatusBadRequest, "Failed to fetch image from url", err)
+			return
+		}
+		if filename == "" {
+			filename = remoteName
+		}
+	} else {
+		fileBytes, err = decodeBase64Image(req.Data)
+		if err != nil {
+			h.respondValidationError(w, []dto.FieldError{{Field: "data", Message: err.Error()}})
+			return
+		}
+	}
+	if filename == "" {
+		h.respondValidationError(w, []dto.FieldError{{Field: "filename", Message: "filename is required"}})
+		return
+	}
+	contentType := http.DetectContentType(fileBytes)
+	if _, mimeType, ok := h.decoders.DetectFormat(fileBytes); ok {
+		contentType = mimeType
+	}
+	if err := h.validateUpload(filename, contentType, int64(len(fileBytes))); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	opts := domain.ProcessingOptions{
+		Pipeline:   req.Pipeline,
+		AutoOrient: req.AutoOrient == nil || *req.AutoOrient,
+		Metadata:   policy,
+		Preset:     req.Preset,
+	}
+	h.upload(w, r, fileBytes, filename, contentType, operations, opts)
+}
+
+func (h *ImageHandler) upload(w http.ResponseWriter, r *http.Request, fileBytes []byte, filename, contentType string, operations []domain.OperationParams, opts domain.ProcessingOptions) {
+	if len(operations) == 0 && opts.Preset == "" {
+		operations = defaultOperations()
+	}
 	image, err := h.usecase.UploadImage(
-		ctx,
+		r.Context(),
 		bytes.NewReader(fileBytes),
-		handler.Filename,
-		handler.Header.Get("Content-Type"),
+		filename,
+		contentType,
 		int64(len(fileBytes)),
 		operations,
+		opts,
 	)
 	if err != nil {
-		h.handleUploadError(w, err, handler.Filename)
+		h.handleUploadError(w, err, filename)
 		return
 	}
 	response := dto.UploadResponse{
@@ -98,19 +266,34 @@ func (h *ImageHandler) GetImage(w http.ResponseWriter, r *http.Request) {
 	req := dto.GetImageRequest{
 		ID:        chi.URLParam(r, "id"),
 		Operation: r.URL.Query().Get("operation"),
+		Variant:   r.URL.Query().Get("variant"),
 	}
 	if req.ID == "" {
 		h.respondError(w, http.StatusBadRequest, "Image ID is required", nil)
 		return
 	}
-	img, reader, err := h.usecase.GetImage(ctx, req.ID, req.Operation)
+	if req.Variant != "" && !domain.IsValidVariantName(req.Variant) {
+		h.respondError(w, http.StatusBadRequest, "Invalid variant name", nil)
+		return
+	}
+	img, processed, reader, err := h.usecase.GetImage(ctx, req.ID, req.Operation, req.Variant)
 	if err != nil {
 		h.handleGetImageError(w, err, req.ID, req.Operation)
 		return
 	}
 	defer reader.Close()
-	filename := h.getDownloadFilename(img.OriginalFilename, req.Operation)
-	w.Header().Set("Content-Type", img.MimeType)
+	suffix := req.Operation
+	if req.Variant != "" {
+		suffix = req.Variant
+	}
+	mimeType, format := img.MimeType, ""
+	if processed != nil {
+		mimeType, format = processed.MimeType, string(processed.Format)
+	} else if domain.IsRedactedOriginalPath(img.OriginalPath) {
+		format = strings.TrimPrefix(filepath.Ext(img.OriginalPath), ".")
+	}
+	filename := h.getDownloadFilename(img.OriginalFilename, suffix, format)
+	w.Header().Set("Content-Type", mimeType)
 	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%s\"", filename))
 	w.Header().Set("Cache-Control", "public, max-age=3600")
 	if _, err := io.Copy(w, reader); err != nil {
@@ -131,14 +314,78 @@ func (h *ImageHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 		h.respondError(w, http.StatusBadRequest, "Image ID is required", nil)
 		return
 	}
-	status, err := h.usecase.GetStatus(ctx, req.ID)
+	img, err := h.usecase.GetStatus(ctx, req.ID)
 	if err != nil {
 		h.handleStatusError(w, err, req.ID)
 		return
 	}
 	response := dto.StatusResponse{
-		ID:     req.ID,
-		Status: string(status),
+		ID:        req.ID,
+		Status:    string(img.Status),
+		BlurHash:  img.Metadata.BlurHash,
+		ThumbHash: img.Metadata.ThumbHash,
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+func (h *ImageHandler) GetMetadata(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.StatusRequest{
+		ID: chi.URLParam(r, "id"),
+	}
+	if req.ID == "" {
+		h.respondError(w, http.StatusBadRequest, "Image ID is required", nil)
+		return
+	}
+	img, err := h.usecase.GetMetadata(ctx, req.ID)
+	if err != nil {
+		h.handleMetadataError(w, err, req.ID)
+		return
+	}
+	metadata := img.Metadata
+	response := dto.MetadataResponse{
+		ID:           img.ID,
+		Filename:     img.OriginalFilename,
+		Size:         img.OriginalSize,
+		MimeType:     img.MimeType,
+		Status:       string(img.Status),
+		Width:        metadata.Width,
+		Height:       metadata.Height,
+		ColorModel:   metadata.ColorModel,
+		BitDepth:     metadata.BitDepth,
+		FrameCount:   metadata.FrameCount,
+		Orientation:  metadata.Orientation,
+		AverageColor: metadata.AverageColor,
+	}
+	for _, color := range metadata.Palette {
+		response.Palette = append(response.Palette, dto.PaletteColorResponse{
+			Color:  color.Color,
+			Weight: color.Weight,
+		})
+	}
+	if report := metadata.Report; report != nil {
+		response.MetadataReport = &dto.MetadataReportResponse{
+			Removed:          report.Removed,
+			Kept:             report.Kept,
+			OriginalScrubbed: report.OriginalScrubbed,
+			OriginalRedacted: report.OriginalRedacted,
+		}
+	}
+	if exif := metadata.Exif; exif != nil {
+		response.Exif = &dto.ExifResponse{
+			CameraMake:  exif.CameraMake,
+			CameraModel: exif.CameraModel,
+			LensModel:   exif.LensModel,
+			Software:    exif.Software,
+			TakenAt:     exif.TakenAt,
+		}
+		if exif.GPS != nil {
+			response.Exif.GPS = &dto.GPSResponse{
+				Latitude:  exif.GPS.Latitude,
+				Longitude: exif.GPS.Longitude,
+				Altitude:  exif.GPS.Altitude,
+			}
+		}
 	}
 	h.respondJSON(w, http.StatusOK, response)
 }
@@ -172,7 +419,12 @@ func (h *ImageHandler) ListImages(w http.ResponseWriter, r *http.Request) {
 	if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
 		offset = o
 	}
-	images, err := h.usecase.ListImages(ctx, limit, offset)
+	filter, fieldErrs := parseColorFilter(r.URL.Query())
+	if len(fieldErrs) > 0 {
+		h.respondValidationError(w, fieldErrs)
+		return
+	}
+	images, err := h.usecase.ListImages(ctx, limit, offset, filter)
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to list images")
 		h.respondError(w, http.StatusInternalServerError, "Failed to list images", err)
@@ -181,99 +433,338 @@ func (h *ImageHandler) ListImages(w http.ResponseWriter, r *http.Request) {
 	response := make([]dto.ImageResponse, len(images))
 	for idx, img := range images {
 		response[idx] = dto.ImageResponse{
-			ID:        img.ID,
-			Filename:  img.OriginalFilename,
-			Size:      img.OriginalSize,
-			Status:    string(img.Status),
-			CreatedAt: img.CreatedAt,
+			ID:           img.ID,
+			Filename:     img.OriginalFilename,
+			Size:         img.OriginalSize,
+			Status:       string(img.Status),
+			Orientation:  img.Metadata.Orientation,
+			BlurHash:     img.Metadata.BlurHash,
+			ThumbHash:    img.Metadata.ThumbHash,
+			AverageColor: img.Metadata.AverageColor,
+			CreatedAt:    img.CreatedAt,
 		}
 	}
 	h.respondJSON(w, http.StatusOK, response)
 }
 
+func parseColorFilter(query url.Values) (*domain.ColorFilter, []dto.FieldError) {
+	color := query.Get("color")
+	if color == "" {
+		return nil, nil
+	}
+	var fieldErrs []dto.FieldError
+	r, g, b, err := domain.ParseHexColor(color)
+	if err != nil {
+		fieldErrs = append(fieldErrs, dto.FieldError{Field: "color", Message: "must be a hex color like #ff8800"})
+	}
+	filter := &domain.ColorFilter{
+		Lab:         domain.LabFromRGB(r, g, b),
+		MaxDistance: domain.DefaultColorDistance,
+		MinWeight:   domain.DefaultColorMinWeight,
+	}
+	if value := query.Get("color_distance"); value != "" {
+		distance, err := parseFiniteFloat(value)
+		if err != nil || distance <= 0 || distance > domain.MaxColorDistance {
+			fieldErrs = append(fieldErrs, dto.FieldError{
+				Field:   "color_distance",
+				Message: fmt.Sprintf("must be a number greater than 0 and at most %d", domain.MaxColorDistance),
+			})
+		}
+		filter.MaxDistance = distance
+	}
+	if value := query.Get("min_weight"); value != "" {
+		weight, err := parseFiniteFloat(value)
+		if err != nil || weight < 0 || weight > 1 {
+			fieldErrs = append(fieldErrs, dto.FieldError{Field: "min_weight", Message: "must be a number between 0 and 1"})
+		}
+		filter.MinWeight = weight
+	}
+	if len(fieldErrs) > 0 {
+		return nil, fieldErrs
+	}
+	return filter, nil
+}
+
+func parseFiniteFloat(value string) (float64, error) {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return 0, fmt.Errorf("%q is not a finite number", value)
+	}
+	return parsed, nil
+}
+
 func (h *ImageHandler) validateFile(handler *multipart.FileHeader) error {
-	if handler.Size > domain.DefaultMaxUploadSize {
+	return h.validateUpload(handler.Filename, handler.Header.Get("Content-Type"), handler.Size)
+}
+
+func (h *ImageHandler) validateUpload(filename, contentType string, size int64) error {
+	if size > domain.DefaultMaxUploadSize {
 		return fmt.Errorf("File is too large (max %d MB)", domain.DefaultMaxUploadSize/(1024*1024))
 	}
-	ext := strings.ToLower(filepath.Ext(handler.Filename))
-	if !h.isValidExtension(ext) {
-		return fmt.Errorf("Unsupported file format. Allowed: jpg, jpeg, png, gif, webp, bmp")
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !h.decoders.IsAllowedExtension(ext) {
+		var allowed []string
+		for _, e := range h.decoders.Extensions() {
+			allowed = append(allowed, strings.TrimPrefix(e, "."))
+		}
+		return fmt.Errorf("Unsupported file format. Allowed: %s", strings.Join(allowed, ", "))
 	}
-	contentType := handler.Header.Get("Content-Type")
 	if !strings.HasPrefix(contentType, "image/") {
 		return fmt.Errorf("File must be an image")
 	}
 	return nil
 }
 
-func (h *ImageHandler) isValidExtension(ext string) bool {
-	allowed := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".gif":  true,
-		".webp": true,
-		".bmp":  true,
-		".tiff": true,
-	}
-	return allowed[ext]
-}
-
-func (h *ImageHandler) parseOperationsFromForm(form url.Values) []domain.OperationParams {
+func (h *ImageHandler) parseOperationsFromForm(form url.Values) ([]domain.OperationParams, error) {
 	var operations []domain.OperationParams
 	if form.Get("thumbnail") == "true" {
 		operations = append(operations, domain.OperationParams{
-			Type: domain.OpThumbnail,
-			Parameters: map[string]interface{}{
-				"size":        200,
-				"crop_to_fit": true,
-			},
+			Type:       domain.OpThumbnail,
+			Parameters: &domain.ThumbnailParams{Size: domain.DefaultThumbnailSize, CropToFit: true},
 		})
 	}
 	if form.Get("resize") == "true" {
 		operations = append(operations, domain.OperationParams{
-			Type: domain.OpResize,
-			Parameters: map[string]interface{}{
-				"width":       1024,
-				"height":      768,
-				"keep_aspect": true,
-			},
+			Type:       domain.OpResize,
+			Parameters: &domain.ResizeParams{Width: 1024, Height: 768, KeepAspect: true},
+		})
+	}
+	if form.Get("adjust") == "true" {
+		params := &domain.AdjustParams{
+			Gamma:        domain.DefaultGamma,
+			AutoLevels:   form.Get("adjust_auto_levels") == "true",
+			WhiteBalance: domain.WhiteBalance(form.Get("adjust_white_balance")),
+		}
+		for _, field := range []struct {
+			name  string
+			value *float64
+		}{
+			{domain.ParamBrightness, &params.Brightness},
+			{domain.ParamContrast, &params.Contrast},
+			{domain.ParamSaturation, &params.Saturation},
+			{domain.ParamGamma, &params.Gamma},
+			{domain.ParamHue, &params.Hue},
+		} {
+			value := form.Get("adjust_" + field.name)
+			if value == "" {
+				continue
+			}
+			parsed, err := parseFiniteFloat(value)
+			if err != nil {
+				return nil, fmt.Errorf("adjust_%s must be a number", field.name)
+			}
+			*field.value = parsed
+		}
+		operations = append(operations, domain.OperationParams{
+			Type:       domain.OpAdjust,
+			Parameters: params,
+		})
+	}
+	if form.Get("filter") == "true" {
+		params := &domain.FilterParams{
+			Filter: domain.FilterGaussianBlur,
+			Radius: domain.DefaultFilterRadius,
+			Amount: domain.DefaultFilterAmount,
+		}
+		if filter := form.Get("filter_type"); filter != "" {
+			params.Filter = domain.ConvolutionFilter(filter)
+		}
+		for _, field := range []struct {
+			name  string
+			value *float64
+		}{
+			{domain.ParamRadius, &params.Radius},
+			{domain.ParamAmount, &params.Amount},
+		} {
+			value := form.Get("filter_" + field.name)
+			if value == "" {
+				continue
+			}
+			parsed, err := parseFiniteFloat(value)
+			if err != nil {
+				return nil, fmt.Errorf("filter_%s must be a number", field.name)
+			}
+			*field.value = parsed
+		}
+		if value := form.Get("filter_threshold"); value != "" {
+			threshold, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("filter_threshold must be an integer")
+			}
+			params.Threshold = threshold
+		}
+		operations = append(operations, domain.OperationParams{
+			Type:       domain.OpFilter,
+			Parameters: params,
 		})
 	}
 	if form.Get("watermark") == "true" {
-		params := map[string]interface{}{
-			"text":     "© ImageProcessor",
-			"opacity":  0.5,
-			"position": "bottom-right",
+		params := &domain.WatermarkParams{
+			Text:      domain.DefaultWatermarkText,
+			Opacity:   domain.DefaultWatermarkOpacity,
+			Position:  domain.WatermarkBottomRight,
+			FontSize:  domain.DefaultWatermarkFontSize,
+			FontColor: domain.DefaultWatermarkFontColor,
+			Scale:     domain.DefaultWatermarkScale,
+			Margin:    domain.DefaultWatermarkMargin,
 		}
 		if text := form.Get("watermark_text"); text != "" {
-			params["text"] = text
+			params.Text = text
 		}
+		params.WatermarkID = form.Get("watermark_id")
 		operations = append(operations, domain.OperationParams{
 			Type:       domain.OpWatermark,
 			Parameters: params,
 		})
 	}
-	if len(operations) == 0 {
-		operations = []domain.OperationParams{
-			{
-				Type: domain.OpThumbnail,
-				Parameters: map[string]interface{}{
-					"size":        200,
-					"crop_to_fit": true,
-				},
-			},
-			{
-				Type: domain.OpResize,
-				Parameters: map[string]interface{}{
-					"width":       1024,
-					"height":      768,
-					"keep_aspect": true,
-				},
-			},
+	if form.Get("crop") == "true" {
+		var values [4]int
+		for i, field := range []string{"x", "y", "width", "height"} {
+			value, err := strconv.Atoi(form.Get("crop_" + field))
+			if err != nil {
+				return nil, fmt.Errorf("crop_%s must be an integer", field)
+			}
+			values[i] = value
+		}
+		operations = append(operations, domain.OperationParams{
+			Type:       domain.OpCrop,
+			Parameters: &domain.CropParams{X: values[0], Y: values[1], Width: values[2], Height: values[3]},
+		})
+	}
+	if form.Get("rotate") == "true" {
+		angle, err := parseFiniteFloat(form.Get("rotate_angle"))
+		if err != nil {
+			return nil, fmt.Errorf("rotate_angle must be a number")
+		}
+		params := &domain.RotateParams{Angle: angle, Background: domain.DefaultRotateBackground}
+		if background := form.Get("rotate_background"); background != "" {
+			params.Background = background
+		}
+		operations = append(operations, domain.OperationParams{
+			Type:       domain.OpRotate,
+			Parameters: params,
+		})
+	}
+	if form.Get("flip") == "true" {
+		params := &domain.FlipParams{Direction: domain.FlipHorizontal}
+		if direction := form.Get("flip_direction"); direction != "" {
+			params.Direction = domain.FlipDirection(direction)
+		}
+		operations = append(operations, domain.OperationParams{
+			Type:       domain.OpFlip,
+			Parameters: params,
+		})
+	}
+	if form.Get("grayscale") == "true" {
+		operations = append(operations, domain.OperationParams{
+			Type:       domain.OpGrayscale,
+			Parameters: &domain.GrayscaleParams{},
+		})
+	}
+	for idx, operation := range operations {
+		if err := operation.Parameters.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s parameters: %w", operation.Type, err)
+		}
+		encoding, err := h.parseEncodingFromForm(form, string(operation.Type))
+		if err != nil {
+			return nil, err
+		}
+		operations[idx].Encoding = encoding
+	}
+	return operations, nil
+}
+
+func (h *ImageHandler) parseEncodingFromForm(form url.Values, prefix string) (domain.OutputOptions, error) {
+	var opts domain.OutputOptions
+	format := form.Get(prefix + "_format")
+	if format == "" {
+		format = form.Get("format")
+	}
+	if format != "" {
+		opts.Format = domain.NormalizeFormat(format)
+		if !h.formats.CanEncode(opts.Format) {
+			return opts, fmt.Errorf("%s: %s", prefix, h.unsupportedFormatMessage(format))
+		}
+	}
+	quality := form.Get(prefix + "_quality")
+	if quality == "" {
+		quality = form.Get("quality")
+	}
+	if quality != "" {
+		value, err := strconv.Atoi(quality)
+		if err != nil {
+			return opts, fmt.Errorf("%s_quality must be an integer", prefix)
+		}
+		opts.Quality = value
+	}
+	if err := opts.Validate(); err != nil {
+		return opts, fmt.Errorf("invalid %s encoding: %w", prefix, err)
+	}
+	return opts, nil
+}
+
+func metadataPolicy(strip *bool, keep []string, stripOriginal bool) (domain.MetadataPolicy, []dto.FieldError) {
+	var fieldErrs []dto.FieldError
+	for idx, group := range keep {
+		if !domain.IsValidMetadataGroup(group) {
+			fieldErrs = append(fieldErrs, dto.FieldError{
+				Field:   fmt.Sprintf("keep_metadata[%d]", idx),
+				Message: fmt.Sprintf("unknown metadata group %q", group),
+			})
+		}
+	}
+	if len(keep) > 0 && strip == nil {
+		strip = boolPtr(true)
+	}
+	return domain.MetadataPolicy{Strip: strip, Keep: keep, StripOriginal: stripOriginal}, fieldErrs
+}
+
+func presetReferenceErrors(name string, hasOperations bool) []dto.FieldError {
+	var fieldErrs []dto.FieldError
+	if !domain.IsValidPresetName(name) {
+		fieldErrs = append(fieldErrs, dto.FieldError{Field: "preset", Message: "preset must match [a-z0-9][a-z0-9_-]{0,99}"})
+	}
+	if hasOperations {
+		fieldErrs = append(fieldErrs, dto.FieldError{Field: "operations", Message: "operations cannot be combined with preset"})
+	}
+	return fieldErrs
+}
+
+func hasOperationFormFields(form url.Values) bool {
+	for key, values := range form {
+		if len(values) == 0 || values[0] == "" {
+			continue
+		}
+		if key == "format" || key == "quality" {
+			return true
 		}
+		for _, flag := range operationFormFlags {
+			if (key == flag && values[0] == "true") || strings.HasPrefix(key, flag+"_") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func defaultOperations() []domain.OperationParams {
+	return []domain.OperationParams{
+		{
+			Type:       domain.OpThumbnail,
+			Parameters: &domain.ThumbnailParams{Size: domain.DefaultThumbnailSize, CropToFit: true},
+		},
+		{
+			Type:       domain.OpResize,
+			Parameters: &domain.ResizeParams{Width: 1024, Height: 768, KeepAspect: true},
+		},
 	}
-	return operations
 }
 
 func (h *ImageHandler) handleUploadError(w http.ResponseWriter, err error, filename string) {
@@ -281,6 +772,9 @@ func (h *ImageHandler) handleUploadError(w http.ResponseWriter, err error, filen
 	case errors.Is(err, image_uc.ErrInvalidFileFormat):
 		h.logger.Warn().Str("filename", filename).Msg("Invalid file format")
 		h.respondError(w, http.StatusBadRequest, "Unsupported file format", nil)
+	case errors.Is(err, image_uc.ErrPresetNotFound):
+		h.logger.Info().Err(err).Str("filename", filename).Msg("Unknown preset")
+		h.respondValidationError(w, []dto.FieldError{{Field: "preset", Message: "preset not found"}})
 	case errors.Is(err, image_uc.ErrFileTooLarge):
 		h.logger.Warn().Str("filename", filename).Msg("File too large")
 		h.respondError(w, http.StatusRequestEntityTooLarge, "File too large", nil)
@@ -314,6 +808,16 @@ func (h *ImageHandler) handleStatusError(w http.ResponseWriter, err error, image
 	}
 }
 
+func (h *ImageHandler) handleMetadataError(w http.ResponseWriter, err error, imageID string) {
+	switch {
+	case errors.Is(err, image_uc.ErrImageNotFound):
+		h.respondError(w, http.StatusNotFound, "Image not found", nil)
+	default:
+		h.logger.Error().Err(err).Str("image_id", imageID).Msg("Failed to get metadata")
+		h.respondError(w, http.StatusInternalServerError, "Failed to get metadata", err)
+	}
+}
+
 func (h *ImageHandler) handleDeleteError(w http.ResponseWriter, err error, imageID string) {
 	switch {
 	case errors.Is(err, image_uc.ErrImageNotFound):
@@ -324,12 +828,24 @@ func (h *ImageHandler) handleDeleteError(w http.ResponseWriter, err error, image
 	}
 }
 
-func (h *ImageHandler) getDownloadFilename(originalName, operation string) string {
-	if operation == "" {
-		return originalName
+func (h *ImageHandler) unsupportedFormatMessage(format string) string {
+	formats := h.formats.Formats()
+	supported := make([]string, len(formats))
+	for idx, name := range formats {
+		supported[idx] = string(name)
 	}
+	return fmt.Sprintf("unsupported output format %q, supported: %s", format, strings.Join(supported, ", "))
+}
+
+func (h *ImageHandler) getDownloadFilename(originalName, operation, format string) string {
 	ext := filepath.Ext(originalName)
 	name := strings.TrimSuffix(originalName, ext)
+	if format != "" {
+		ext = "." + format
+	}
+	if operation == "" {
+		return name + ext
+	}
 	return fmt.Sprintf("%s_%s%s", name, operation, ext)
 }
 
@@ -342,6 +858,14 @@ func (h *ImageHandler) respondJSON(w http.ResponseWriter, status int, data inter
 	}
 }
 
+func (h *ImageHandler) respondValidationError(w http.ResponseWriter, fields []dto.FieldError) {
+	h.respondJSON(w, http.StatusBadRequest, dto.ErrorResponse{
+		Error:   http.StatusText(http.StatusBadRequest),
+		Message: "Request validation failed",
+		Fields:  fields,
+	})
+}
+
 func (h *ImageHandler) respondError(w http.ResponseWriter, status int, message string, err error) {
 	response := dto.ErrorResponse{
 		Error:   http.StatusText(status),
@@ -0,0 +1,129 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/http-server/handler/image/dto"
+)
+
+const maxOperations = 20
+
+func (h *ImageHandler) ListOperations(w http.ResponseWriter, r *http.Request) {
+	descriptors := h.catalog.DescribeAll()
+	response := make([]dto.OperationResponse, len(descriptors))
+	for idx, desc := range descriptors {
+		params := make([]dto.OperationParamResponse, len(desc.Params))
+		for i, param := range desc.Params {
+			params[i] = dto.OperationParamResponse{
+				Name:    param.Name,
+				Type:    param.Type,
+				Default: param.Default,
+			}
+		}
+		response[idx] = dto.OperationResponse{
+			Name:         string(desc.Name),
+			Params:       params,
+			PathTemplate: desc.PathTemplate,
+			Capabilities: dto.OperationCapabilities{
+				Chainable:         desc.Capabilities.Chainable,
+				PreservesGIF:      desc.Capabilities.PreservesGIF,
+				ChangesDimensions: desc.Capabilities.ChangesDimensions,
+				MultiOutput:       desc.Capabilities.MultiOutput,
+			},
+		}
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+func (h *ImageHandler) validateOperationSpecs(specs []dto.OperationSpec, pipeline bool) ([]domain.OperationParams, []dto.FieldError) {
+	var errs []dto.FieldError
+	if len(specs) > maxOperations {
+		errs = append(errs, dto.FieldError{Field: "operations", Message: fmt.Sprintf("at most %d operations are allowed", maxOperations)})
+		return nil, errs
+	}
+	operations := make([]domain.OperationParams, 0, len(specs))
+	outputs := make(map[string]bool)
+	redactsOriginal := true
+	for idx, spec := range specs {
+		desc, ok := h.catalog.Describe(domain.OperationType(spec.Type))
+		if !ok {
+			errs = append(errs, paramFieldErrors(idx, spec.Type, domain.ErrUnsupportedOperation)...)
+			continue
+		}
+		if pipeline && !desc.Capabilities.Chainable {
+			errs = append(errs, dto.FieldError{Field: fmt.Sprintf("operations[%d].type", idx), Message: fmt.Sprintf("operation %q cannot be used in a pipeline", spec.Type)})
+			continue
+		}
+		params, err := domain.DecodeParams(desc.Name, spec.Params)
+		if err != nil {
+			errs = append(errs, paramFieldErrors(idx, spec.Type, err)...)
+			continue
+		}
+		if redact, ok := params.(*domain.RedactParams); !ok || !redact.ReplaceOriginal {
+			redactsOriginal = false
+		} else if pipeline && !redactsOriginal {
+			errs = append(errs, dto.FieldError{Field: fmt.Sprintf("operations[%d].params.replace_original", idx), Message: "redactions of the original must precede other pipeline steps"})
+		}
+		encoding, err := domain.DecodeOutputOptions(spec.Encoding)
+		if err != nil {
+			errs = append(errs, encodingFieldErrors(idx, err)...)
+		} else if encoding.Format != "" && !h.formats.CanEncode(encoding.Format) {
+			errs = append(errs, dto.FieldError{Field: fmt.Sprintf("operations[%d].encoding.format", idx), Message: h.unsupportedFormatMessage(string(encoding.Format))})
+		}
+		if pipeline && !encoding.IsZero() && spec.Output == "" && idx != len(specs)-1 {
+			errs = append(errs, dto.FieldError{Field: fmt.Sprintf("operations[%d].encoding", idx), Message: "encoding is only allowed on pipeline outputs"})
+		}
+		if spec.Output != "" {
+			field := fmt.Sprintf("operations[%d].output", idx)
+			switch {
+			case !pipeline:
+				errs = append(errs, dto.FieldError{Field: field, Message: "output is only allowed in pipeline mode"})
+			case !domain.IsValidVariantName(spec.Output):
+				errs = append(errs, dto.FieldError{Field: field, Message: "output must match [a-zA-Z0-9_-]{1,100}"})
+			case outputs[spec.Output]:
+				errs = append(errs, dto.FieldError{Field: field, Message: "duplicate output name"})
+			}
+			outputs[spec.Output] = true
+		}
+		operations = append(operations, domain.OperationParams{
+			Type:       domain.OperationType(spec.Type),
+			Parameters: params,
+			Output:     spec.Output,
+			Encoding:   encoding,
+		})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return operations, nil
+}
+
+func paramFieldErrors(idx int, opType string, err error) []dto.FieldError {
+	if errors.Is(err, domain.ErrUnsupportedOperation) {
+		return []dto.FieldError{{Field: fmt.Sprintf("operations[%d].type", idx), Message: fmt.Sprintf("unsupported operation type %q", opType)}}
+	}
+	var paramErrs domain.ParamErrors
+	if !errors.As(err, &paramErrs) {
+		return []dto.FieldError{{Field: fmt.Sprintf("operations[%d].params", idx), Message: err.Error()}}
+	}
+	fields := make([]dto.FieldError, len(paramErrs))
+	for i, pe := range paramErrs {
+		fields[i] = dto.FieldError{Field: fmt.Sprintf("operations[%d].params.%s", idx, pe.Param), Message: pe.Message}
+	}
+	return fields
+}
+
+func encodingFieldErrors(idx int, err error) []dto.FieldError {
+	var paramErrs domain.ParamErrors
+	if !errors.As(err, &paramErrs) {
+		return []dto.FieldError{{Field: fmt.Sprintf("operations[%d].encoding", idx), Message: err.Error()}}
+	}
+	fields := make([]dto.FieldError, len(paramErrs))
+	for i, pe := range paramErrs {
+		fields[i] = dto.FieldError{Field: fmt.Sprintf("operations[%d].encoding.%s", idx, pe.Param), Message: pe.Message}
+	}
+	return fields
+}
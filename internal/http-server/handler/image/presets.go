@@ -0,0 +1,146 @@
+package image
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/http-server/handler/image/dto"
+	preset_uc "image-processor/internal/usecase/preset"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxPresetBodySize = 64 << 10
+
+func (h *ImageHandler) ListPresets(w http.ResponseWriter, r *http.Request) {
+	presets, err := h.presets.ListPresets(r.Context())
+	if err != nil {
+		h.handlePresetError(w, err, "")
+		return
+	}
+	response := make([]dto.PresetResponse, len(presets))
+	for idx := range presets {
+		response[idx] = presetResponse(&presets[idx])
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+func (h *ImageHandler) GetPreset(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	preset, err := h.presets.GetPreset(r.Context(), name)
+	if err != nil {
+		h.handlePresetError(w, err, name)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, presetResponse(preset))
+}
+
+func (h *ImageHandler) CreatePreset(w http.ResponseWriter, r *http.Request) {
+	preset, ok := h.decodePreset(w, r, "")
+	if !ok {
+		return
+	}
+	if err := h.presets.CreatePreset(r.Context(), preset); err != nil {
+		h.handlePresetError(w, err, preset.Name)
+		return
+	}
+	h.respondJSON(w, http.StatusCreated, presetResponse(preset))
+}
+
+func (h *ImageHandler) UpdatePreset(w http.ResponseWriter, r *http.Request) {
+	preset, ok := h.decodePreset(w, r, chi.URLParam(r, "name"))
+	if !ok {
+		return
+	}
+	if err := h.presets.UpdatePreset(r.Context(), preset); err != nil {
+		h.handlePresetError(w, err, preset.Name)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, presetResponse(preset))
+}
+
+func (h *ImageHandler) DeletePreset(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if err := h.presets.DeletePreset(r.Context(), name); err != nil {
+		h.handlePresetError(w, err, name)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ImageHandler) decodePreset(w http.ResponseWriter, r *http.Request, name string) (*domain.Preset, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxPresetBodySize)
+	var req dto.PresetRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid JSON body", err)
+		return nil, false
+	}
+	if name == "" {
+		name = req.Name
+	}
+	var fieldErrs []dto.FieldError
+	switch {
+	case !domain.IsValidPresetName(name):
+		fieldErrs = append(fieldErrs, dto.FieldError{Field: "name", Message: "name must match [a-z0-9][a-z0-9_-]{0,99}"})
+	case req.Name != "" && req.Name != name:
+		fieldErrs = append(fieldErrs, dto.FieldError{Field: "name", Message: "name does not match the preset in the URL"})
+	}
+	if len(req.Description) > domain.MaxPresetDescriptionLength {
+		fieldErrs = append(fieldErrs, dto.FieldError{Field: "description", Message: fmt.Sprintf("must be at most %d characters", domain.MaxPresetDescriptionLength)})
+	}
+	if len(req.Operations) == 0 {
+		fieldErrs = append(fieldErrs, dto.FieldError{Field: "operations", Message: "at least one operation is required"})
+	}
+	operations, opErrs := h.validateOperationSpecs(req.Operations, req.Pipeline)
+	fieldErrs = append(fieldErrs, opErrs...)
+	if len(fieldErrs) > 0 {
+		h.respondValidationError(w, fieldErrs)
+		return nil, false
+	}
+	return &domain.Preset{
+		Name:        name,
+		Description: req.Description,
+		Pipeline:    req.Pipeline,
+		Operations:  operations,
+	}, true
+}
+
+func presetResponse(preset *domain.Preset) dto.PresetResponse {
+	operations := make([]dto.OperationSpec, len(preset.Operations))
+	for idx, operation := range preset.Operations {
+		operations[idx] = dto.OperationSpec{
+			Type:   string(operation.Type),
+			Params: json.RawMessage(domain.CanonicalParams(operation.Parameters)),
+			Output: operation.Output,
+		}
+		if !operation.Encoding.IsZero() {
+			operations[idx].Encoding, _ = json.Marshal(operation.Encoding)
+		}
+	}
+	return dto.PresetResponse{
+		Name:        preset.Name,
+		Description: preset.Description,
+		Pipeline:    preset.Pipeline,
+		Version:     preset.Version,
+		Operations:  operations,
+		CreatedAt:   preset.CreatedAt,
+		UpdatedAt:   preset.UpdatedAt,
+	}
+}
+
+func (h *ImageHandler) handlePresetError(w http.ResponseWriter, err error, name string) {
+	switch {
+	case errors.Is(err, preset_uc.ErrPresetNotFound):
+		h.respondError(w, http.StatusNotFound, "Preset not found", nil)
+	case errors.Is(err, preset_uc.ErrPresetExists):
+		h.respondError(w, http.StatusConflict, "Preset already exists", nil)
+	default:
+		h.logger.Error().Err(err).Str("preset", name).Msg("Preset request failed")
+		h.respondError(w, http.StatusInternalServerError, "Failed to process preset request", err)
+	}
+}
@@ -0,0 +1,157 @@
+package image
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"image-processor/internal/domain"
+)
+
+const remoteFetchTimeout = 30 * time.Second
+
+var remoteClient = &http.Client{
+	Timeout: remoteFetchTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: rejectInternalAddress,
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 15 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 3 {
+			return fmt.Errorf("%w: too many redirects", ErrRemoteFetch)
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return ErrInvalidRemoteURL
+		}
+		return nil
+	},
+}
+
+var deniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("192.88.99.0/24"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("224.0.0.0/4"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("::/96"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/32"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("fc00::/7"),
+	netip.MustParsePrefix("fe80::/10"),
+	netip.MustParsePrefix("ff00::/8"),
+}
+
+var (
+	nat64Prefixes = []netip.Prefix{
+		netip.MustParsePrefix("64:ff9b::/96"),
+		netip.MustParsePrefix("64:ff9b:1::/48"),
+	}
+	sixToFourPrefix = netip.MustParsePrefix("2002::/16")
+)
+
+func rejectInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRemoteAddress, err)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || isInternalAddress(addr) {
+		return fmt.Errorf("%w: %s", ErrRemoteAddress, host)
+	}
+	return nil
+}
+
+func isInternalAddress(addr netip.Addr) bool {
+	addr = addr.Unmap().WithZone("")
+	for _, prefix := range deniedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	if !addr.Is6() {
+		return false
+	}
+	raw := addr.As16()
+	for _, prefix := range nat64Prefixes {
+		if prefix.Contains(addr) {
+			return isInternalAddress(netip.AddrFrom4([4]byte(raw[12:16])))
+		}
+	}
+	if sixToFourPrefix.Contains(addr) {
+		return isInternalAddress(netip.AddrFrom4([4]byte(raw[2:6])))
+	}
+	return false
+}
+
+func fetchRemoteImage(ctx context.Context, rawURL string) ([]byte, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, "", ErrInvalidRemoteURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidRemoteURL, err)
+	}
+	resp, err := remoteClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrRemoteFetch, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%w: unexpected status %d", ErrRemoteFetch, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, domain.DefaultMaxUploadSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrRemoteFetch, err)
+	}
+	if len(data) > domain.DefaultMaxUploadSize {
+		return nil, "", fmt.Errorf("%w: file is too large", ErrRemoteFetch)
+	}
+	filename := path.Base(u.Path)
+	if filename == "/" || filename == "." {
+		filename = ""
+	}
+	return data, filename, nil
+}
+
+func decodeBase64Image(payload string) ([]byte, error) {
+	if strings.HasPrefix(payload, "data:") {
+		idx := strings.Index(payload, ",")
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: malformed data URI", ErrInvalidBase64)
+		}
+		payload = payload[idx+1:]
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBase64, err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty payload", ErrInvalidBase64)
+	}
+	return data, nil
+}
@@ -0,0 +1,79 @@
+package image
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestIsInternalAddress(t *testing.T) {
+	tests := []struct {
+		addr     string
+		internal bool
+	}{
+		{"8.8.8.8", false},
+		{"93.184.216.34", false},
+		{"2606:4700:4700::1111", false},
+		{"0.0.0.0", true},
+		{"10.1.2.3", true},
+		{"100.64.0.1", true},
+		{"127.0.0.1", true},
+		{"169.254.169.254", true},
+		{"172.16.0.1", true},
+		{"172.31.255.255", true},
+		{"172.32.0.1", false},
+		{"192.168.1.1", true},
+		{"198.18.0.1", true},
+		{"224.0.0.1", true},
+		{"255.255.255.255", true},
+		{"::", true},
+		{"::1", true},
+		{"fc00::1", true},
+		{"fd12:3456::1", true},
+		{"fe80::1", true},
+		{"fe80::1%eth0", true},
+		{"ff02::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"::ffff:8.8.8.8", false},
+		{"64:ff9b::7f00:1", true},
+		{"64:ff9b::a9fe:a9fe", true},
+		{"64:ff9b::808:808", false},
+		{"2002:7f00:1::", true},
+		{"2002:c0a8:101::1", true},
+		{"2002:808:808::", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := isInternalAddress(netip.MustParseAddr(tt.addr)); got != tt.internal {
+				t.Errorf("isInternalAddress(%s) = %v, want %v", tt.addr, got, tt.internal)
+			}
+		})
+	}
+}
+
+func TestRejectInternalAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{"8.8.8.8:443", false},
+		{"[2606:4700:4700::1111]:80", false},
+		{"127.0.0.1:80", true},
+		{"[::1]:443", true},
+		{"[::ffff:192.168.0.1]:80", true},
+		{"localhost:80", true},
+		{"8.8.8.8", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := rejectInternalAddress("tcp", tt.address, nil)
+			if tt.wantErr && !errors.Is(err, ErrRemoteAddress) {
+				t.Errorf("rejectInternalAddress(%s) error = %v, want %v", tt.address, err, ErrRemoteAddress)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("rejectInternalAddress(%s) error = %v, want nil", tt.address, err)
+			}
+		})
+	}
+}
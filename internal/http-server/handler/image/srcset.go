@@ -0,0 +1,108 @@
+package image
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/http-server/handler/image/dto"
+	image_uc "image-processor/internal/usecase/image"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *ImageHandler) GetSrcset(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		h.respondError(w, http.StatusBadRequest, "Image ID is required", nil)
+		return
+	}
+	format := domain.NormalizeFormat(r.URL.Query().Get("format"))
+	img, variants, err := h.usecase.GetSrcset(r.Context(), id)
+	if err != nil {
+		h.handleSrcsetError(w, err, id)
+		return
+	}
+	base := strings.TrimSuffix(r.URL.Path, "/srcset")
+	response := dto.SrcsetResponse{ID: img.ID}
+	sets := make(map[domain.ImageFormat]*dto.SrcsetFormatResponse)
+	var order []domain.ImageFormat
+	for _, variant := range variants {
+		if format != "" && variant.Format != format {
+			continue
+		}
+		var output domain.ResponsiveOutput
+		if err := json.Unmarshal([]byte(variant.Parameters), &output); err != nil {
+			h.logger.Warn().Err(err).Str("image_id", id).Str("variant", variant.Variant).Msg("Skipping responsive variant with invalid parameters")
+			continue
+		}
+		if response.Sizes == "" {
+			response.Sizes = output.Sizes
+		}
+		set, ok := sets[variant.Format]
+		if !ok {
+			set = &dto.SrcsetFormatResponse{Format: string(variant.Format), MimeType: variant.MimeType}
+			sets[variant.Format] = set
+			order = append(order, variant.Format)
+		}
+		set.Candidates = append(set.Candidates, dto.SrcsetCandidate{
+			URL:        base + "?" + url.Values{"variant": {variant.Variant}}.Encode(),
+			Width:      output.Width,
+			Height:     output.Height,
+			Descriptor: output.Descriptor,
+			Size:       variant.Size,
+		})
+	}
+	if len(order) == 0 {
+		h.respondError(w, http.StatusNotFound, fmt.Sprintf("Responsive variants not found for format %q", format), nil)
+		return
+	}
+	for _, f := range order {
+		response.Formats = append(response.Formats, buildSrcset(*sets[f]))
+	}
+	if response.Sizes == "" {
+		response.Sizes = domain.DefaultResponsiveSizes
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+func buildSrcset(set dto.SrcsetFormatResponse) dto.SrcsetFormatResponse {
+	sort.SliceStable(set.Candidates, func(i, j int) bool {
+		return set.Candidates[i].Width < set.Candidates[j].Width
+	})
+	var widths, densities []string
+	for _, candidate := range set.Candidates {
+		entry := candidate.URL + " " + candidate.Descriptor
+		if strings.HasSuffix(candidate.Descriptor, "x") {
+			densities = append(densities, entry)
+			if candidate.Descriptor == "1x" {
+				set.Src = candidate.URL
+			}
+		} else {
+			widths = append(widths, entry)
+		}
+	}
+	if set.Src == "" {
+		set.Src = set.Candidates[len(set.Candidates)-1].URL
+	}
+	set.Srcset = strings.Join(widths, ", ")
+	set.DensitySrcset = strings.Join(densities, ", ")
+	return set
+}
+
+func (h *ImageHandler) handleSrcsetError(w http.ResponseWriter, err error, imageID string) {
+	switch {
+	case errors.Is(err, image_uc.ErrImageNotFound):
+		h.respondError(w, http.StatusNotFound, "Image not found", nil)
+	case errors.Is(err, image_uc.ErrProcessedImageNotFound):
+		h.respondError(w, http.StatusNotFound, "Responsive variants not found", nil)
+	default:
+		h.logger.Error().Err(err).Str("image_id", imageID).Msg("Failed to get srcset")
+		h.respondError(w, http.StatusInternalServerError, "Failed to get srcset", err)
+	}
+}
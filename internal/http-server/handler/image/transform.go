@@ -0,0 +1,294 @@
+package image
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/http-server/handler/image/dto"
+	image_uc "image-processor/internal/usecase/image"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	maxSignRequestSize = 4 << 10
+	maxSignedURLTTL    = 365 * 24 * time.Hour
+)
+
+var transformQueryFields = map[string]string{
+	domain.ParamWidth:      "w",
+	domain.ParamHeight:     "h",
+	domain.ParamFit:        "fit",
+	domain.ParamGravity:    "g",
+	domain.ParamBackground: "bg",
+	domain.ParamNoUpscale:  "no_upscale",
+	domain.ParamFormat:     "fmt",
+	domain.ParamQuality:    "q",
+}
+
+func (h *ImageHandler) TransformImage(w http.ResponseWriter, r *http.Request) {
+	req := transformRequest(chi.URLParam(r, "id"), r.URL.Query())
+	if req.ID == "" {
+		h.respondError(w, http.StatusBadRequest, "Image ID is required", nil)
+		return
+	}
+	operations, fieldErrs := h.parseTransformRequest(req)
+	if len(fieldErrs) > 0 {
+		h.respondValidationError(w, fieldErrs)
+		return
+	}
+	processed, reader, err := h.usecase.TransformImage(r.Context(), req.ID, operations, req.Preset)
+	if err != nil {
+		h.handleTransformError(w, err, req.ID)
+		return
+	}
+	defer reader.Close()
+	w.Header().Set("Content-Type", processed.MimeType)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("ETag", fmt.Sprintf("%q", processed.CacheKey))
+	if processed.CropBox != nil {
+		w.Header().Set("X-Crop-Box", processed.CropBox.String())
+	}
+	if _, err := io.Copy(w, reader); err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("image_id", req.ID).
+			Str("cache_key", processed.CacheKey).
+			Msg("Failed to stream transformed image")
+	}
+}
+
+func (h *ImageHandler) SignTransformURL(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		h.respondError(w, http.StatusBadRequest, "Image ID is required", nil)
+		return
+	}
+	if !h.signer.Enabled() {
+		h.respondError(w, http.StatusNotImplemented, "URL signing is not configured", nil)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxSignRequestSize)
+	var req dto.SignTransformRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid JSON body", err)
+		return
+	}
+	query := url.Values{}
+	setInt := func(key string, value int) {
+		if value != 0 {
+			query.Set(key, strconv.Itoa(value))
+		}
+	}
+	setInt("w", req.Width)
+	setInt("h", req.Height)
+	setInt("q", req.Quality)
+	setFloat := func(key string, value float64) {
+		if value != 0 {
+			query.Set(key, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+	}
+	setFloat(domain.ParamBrightness, req.Brightness)
+	setFloat(domain.ParamContrast, req.Contrast)
+	setFloat(domain.ParamSaturation, req.Saturation)
+	setFloat(domain.ParamGamma, req.Gamma)
+	setFloat(domain.ParamHue, req.Hue)
+	if req.AutoLevels {
+		query.Set(domain.ParamAutoLevels, "true")
+	}
+	if req.WhiteBalance != "" {
+		query.Set(domain.ParamWhiteBalance, req.WhiteBalance)
+	}
+	if req.Fit != "" {
+		query.Set("fit", req.Fit)
+	}
+	if req.Gravity != "" {
+		query.Set("g", req.Gravity)
+	}
+	if req.Bg != "" {
+		query.Set("bg", req.Bg)
+	}
+	if req.NoUpscale {
+		query.Set("no_upscale", "true")
+	}
+	if req.Format != "" {
+		query.Set("fmt", req.Format)
+	}
+	if req.Preset != "" {
+		query.Set("preset", req.Preset)
+	}
+	_, fieldErrs := h.parseTransformRequest(transformRequest(id, query))
+	ttl := h.signer.DefaultTTL()
+	if req.TTLSeconds != 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+		if req.TTLSeconds < 0 || ttl > maxSignedURLTTL {
+			fieldErrs = append(fieldErrs, dto.FieldError{Field: "ttl_seconds", Message: fmt.Sprintf("must be between 1 and %d", int(maxSignedURLTTL.Seconds()))})
+		}
+	}
+	if len(fieldErrs) > 0 {
+		h.respondValidationError(w, fieldErrs)
+		return
+	}
+	expiresAt := time.Now().Add(ttl).UTC().Truncate(time.Second)
+	path := strings.TrimSuffix(r.URL.Path, "/sign")
+	h.respondJSON(w, http.StatusOK, dto.SignedURLResponse{
+		URL:       h.signer.Sign(path, query, expiresAt),
+		ExpiresAt: expiresAt,
+	})
+}
+
+func transformRequest(id string, query url.Values) dto.TransformRequest {
+	return dto.TransformRequest{
+		ID:           id,
+		Width:        query.Get("w"),
+		Height:       query.Get("h"),
+		Fit:          query.Get("fit"),
+		Gravity:      query.Get("g"),
+		Bg:           query.Get("bg"),
+		NoUpscale:    query.Get("no_upscale"),
+		Format:       query.Get("fmt"),
+		Quality:      query.Get("q"),
+		Brightness:   query.Get(domain.ParamBrightness),
+		Contrast:     query.Get(domain.ParamContrast),
+		Saturation:   query.Get(domain.ParamSaturation),
+		Gamma:        query.Get(domain.ParamGamma),
+		Hue:          query.Get(domain.ParamHue),
+		AutoLevels:   query.Get(domain.ParamAutoLevels),
+		WhiteBalance: query.Get(domain.ParamWhiteBalance),
+		Preset:       query.Get("preset"),
+	}
+}
+
+func (h *ImageHandler) parseTransformRequest(req dto.TransformRequest) ([]domain.OperationParams, []dto.FieldError) {
+	hasResize := req.Width != "" || req.Height != "" || req.Fit != "" || req.Gravity != "" || req.Bg != "" || req.NoUpscale != ""
+	hasAdjust := req.Brightness != "" || req.Contrast != "" || req.Saturation != "" || req.Gamma != "" ||
+		req.Hue != "" || req.AutoLevels != "" || req.WhiteBalance != ""
+	if req.Preset != "" {
+		fieldErrs := presetReferenceErrors(req.Preset, false)
+		if hasResize || hasAdjust || req.Format != "" || req.Quality != "" {
+			fieldErrs = append(fieldErrs, dto.FieldError{Field: "preset", Message: "preset cannot be combined with other transform parameters"})
+		}
+		return nil, fieldErrs
+	}
+	var fieldErrs []dto.FieldError
+	parseInt := func(field, value string) int {
+		if value == "" {
+			return 0
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			fieldErrs = append(fieldErrs, dto.FieldError{Field: field, Message: "must be an integer"})
+		}
+		return parsed
+	}
+	parseFloat := func(field, value string, fallback float64) float64 {
+		if value == "" {
+			return fallback
+		}
+		parsed, err := parseFiniteFloat(value)
+		if err != nil {
+			fieldErrs = append(fieldErrs, dto.FieldError{Field: field, Message: "must be a number"})
+		}
+		return parsed
+	}
+	parseBool := func(field, value string) bool {
+		if value == "" {
+			return false
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			fieldErrs = append(fieldErrs, dto.FieldError{Field: field, Message: "must be a boolean"})
+		}
+		return parsed
+	}
+	var operations []domain.OperationParams
+	if hasResize || !hasAdjust {
+		params := &domain.ResizeParams{
+			Width:      parseInt("w", req.Width),
+			Height:     parseInt("h", req.Height),
+			Fit:        domain.ResizeFit(req.Fit),
+			Gravity:    domain.Gravity(req.Gravity),
+			Background: req.Bg,
+			NoUpscale:  parseBool("no_upscale", req.NoUpscale),
+		}
+		if params.Fit == "" {
+			params.Fit = domain.FitInside
+		}
+		operations = append(operations, domain.OperationParams{Type: domain.OpResize, Parameters: params})
+	}
+	if hasAdjust {
+		operations = append(operations, domain.OperationParams{Type: domain.OpAdjust, Parameters: &domain.AdjustParams{
+			Brightness:   parseFloat(domain.ParamBrightness, req.Brightness, 0),
+			Contrast:     parseFloat(domain.ParamContrast, req.Contrast, 0),
+			Saturation:   parseFloat(domain.ParamSaturation, req.Saturation, 0),
+			Gamma:        parseFloat(domain.ParamGamma, req.Gamma, domain.DefaultGamma),
+			Hue:          parseFloat(domain.ParamHue, req.Hue, 0),
+			AutoLevels:   parseBool(domain.ParamAutoLevels, req.AutoLevels),
+			WhiteBalance: domain.WhiteBalance(req.WhiteBalance),
+		}})
+	}
+	encoding := domain.OutputOptions{
+		Format:  domain.NormalizeFormat(req.Format),
+		Quality: parseInt("q", req.Quality),
+	}
+	if len(fieldErrs) > 0 {
+		return nil, fieldErrs
+	}
+	for _, operation := range operations {
+		fieldErrs = append(fieldErrs, transformFieldErrors(operation.Parameters.Validate())...)
+	}
+	fieldErrs = append(fieldErrs, transformFieldErrors(encoding.Validate())...)
+	if encoding.Format != "" && !h.formats.CanEncode(encoding.Format) {
+		fieldErrs = append(fieldErrs, dto.FieldError{Field: "fmt", Message: h.unsupportedFormatMessage(req.Format)})
+	}
+	operations[len(operations)-1].Encoding = encoding
+	return operations, fieldErrs
+}
+
+func transformFieldErrors(err error) []dto.FieldError {
+	if err == nil {
+		return nil
+	}
+	var paramErrs domain.ParamErrors
+	if !errors.As(err, &paramErrs) {
+		return []dto.FieldError{{Field: "query", Message: err.Error()}}
+	}
+	fields := make([]dto.FieldError, len(paramErrs))
+	for i, pe := range paramErrs {
+		field, ok := transformQueryFields[pe.Param]
+		if !ok {
+			field = pe.Param
+		}
+		fields[i] = dto.FieldError{Field: field, Message: pe.Message}
+	}
+	return fields
+}
+
+func (h *ImageHandler) handleTransformError(w http.ResponseWriter, err error, imageID string) {
+	switch {
+	case errors.Is(err, image_uc.ErrImageNotFound):
+		h.respondError(w, http.StatusNotFound, "Image not found", nil)
+	case errors.Is(err, image_uc.ErrPresetNotFound):
+		h.respondValidationError(w, []dto.FieldError{{Field: "preset", Message: "preset not found"}})
+	case errors.Is(err, image_uc.ErrPresetNotPipeline):
+		h.respondValidationError(w, []dto.FieldError{{Field: "preset", Message: "preset defines independent outputs; only pipeline or single-operation presets can be used for transforms"}})
+	case errors.Is(err, image_uc.ErrReplacesOriginal):
+		h.respondValidationError(w, []dto.FieldError{{Field: "preset", Message: "preset replaces the original and can only be used for uploads"}})
+	case errors.Is(err, image_uc.ErrTransformFailed):
+		h.logger.Warn().Err(err).Str("image_id", imageID).Msg("Transform failed")
+		h.respondError(w, http.StatusUnprocessableEntity, "Failed to transform image", err)
+	default:
+		h.logger.Error().Err(err).Str("image_id", imageID).Msg("Failed to transform image")
+		h.respondError(w, http.StatusInternalServerError, "Failed to transform image", err)
+	}
+}
@@ -0,0 +1,114 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/http-server/handler/image/dto"
+	watermark_uc "image-processor/internal/usecase/watermark"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *ImageHandler) UploadWatermark(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxWatermarkAssetSize+64<<10)
+	if err := r.ParseMultipartForm(domain.MaxWatermarkAssetSize); err != nil {
+		h.logger.Warn().Err(err).Msg("Failed to parse watermark form")
+		h.respondError(w, http.StatusBadRequest, "Invalid request format", nil)
+		return
+	}
+	file, handler, err := r.FormFile("file")
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "File is required", nil)
+		return
+	}
+	defer file.Close()
+	if err := h.validateFile(handler); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	asset, err := h.watermarks.UploadWatermark(r.Context(), file, handler.Filename)
+	if err != nil {
+		h.handleWatermarkError(w, err, "")
+		return
+	}
+	h.respondJSON(w, http.StatusCreated, watermarkResponse(asset))
+}
+
+func (h *ImageHandler) ListWatermarks(w http.ResponseWriter, r *http.Request) {
+	assets, err := h.watermarks.ListWatermarks(r.Context())
+	if err != nil {
+		h.handleWatermarkError(w, err, "")
+		return
+	}
+	response := make([]dto.WatermarkResponse, len(assets))
+	for idx := range assets {
+		response[idx] = watermarkResponse(&assets[idx])
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+func (h *ImageHandler) GetWatermark(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !domain.IsValidWatermarkID(id) {
+		h.respondError(w, http.StatusNotFound, "Watermark not found", nil)
+		return
+	}
+	asset, reader, err := h.watermarks.GetWatermark(r.Context(), id)
+	if err != nil {
+		h.handleWatermarkError(w, err, id)
+		return
+	}
+	defer reader.Close()
+	w.Header().Set("Content-Type", asset.MimeType)
+	w.Header().Set("Content-Length", strconv.FormatInt(asset.Size, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", asset.Filename))
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	if _, err := io.Copy(w, reader); err != nil {
+		h.logger.Error().Err(err).Str("watermark_id", id).Msg("Failed to stream watermark")
+	}
+}
+
+func (h *ImageHandler) DeleteWatermark(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !domain.IsValidWatermarkID(id) {
+		h.respondError(w, http.StatusNotFound, "Watermark not found", nil)
+		return
+	}
+	if err := h.watermarks.DeleteWatermark(r.Context(), id); err != nil {
+		h.handleWatermarkError(w, err, id)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func watermarkResponse(asset *domain.WatermarkAsset) dto.WatermarkResponse {
+	return dto.WatermarkResponse{
+		ID:        asset.ID,
+		Filename:  asset.Filename,
+		MimeType:  asset.MimeType,
+		Format:    string(asset.Format),
+		Width:     asset.Width,
+		Height:    asset.Height,
+		Size:      asset.Size,
+		CreatedAt: asset.CreatedAt,
+	}
+}
+
+func (h *ImageHandler) handleWatermarkError(w http.ResponseWriter, err error, id string) {
+	switch {
+	case errors.Is(err, watermark_uc.ErrWatermarkNotFound):
+		h.respondError(w, http.StatusNotFound, "Watermark not found", nil)
+	case errors.Is(err, watermark_uc.ErrInvalidFileFormat):
+		h.respondError(w, http.StatusBadRequest, "Unsupported file format", nil)
+	case errors.Is(err, watermark_uc.ErrFileTooLarge):
+		h.respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Watermark is too large (max %d MB, %dx%d px)", domain.MaxWatermarkAssetSize>>20, domain.MaxWatermarkAssetPixel, domain.MaxWatermarkAssetPixel), nil)
+	default:
+		h.logger.Error().Err(err).Str("watermark_id", id).Msg("Watermark request failed")
+		h.respondError(w, http.StatusInternalServerError, "Failed to process watermark request", err)
+	}
+}
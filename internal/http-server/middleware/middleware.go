@@ -1,12 +1,19 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"encoding/json"
 	"net/http"
 	"time"
 
+	"image-processor/internal/http-server/handler/image/dto"
+	"image-processor/internal/http-server/signature"
+
 	"github.com/wb-go/wbf/zlog"
 )
 
+const APIKeyHeader = "X-API-Key"
+
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -38,3 +45,50 @@ func RecoveryMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+func SignatureMiddleware(signer *signature.Signer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !signer.Enabled() {
+				respondForbidden(w, "URL signing is not configured", "")
+				return
+			}
+			if err := signer.Verify(r.URL.Path, r.URL.Query(), time.Now()); err != nil {
+				zlog.Logger.Warn().
+					Err(err).
+					Str("path", r.URL.Path).
+					Str("query", r.URL.RawQuery).
+					Msg("Rejected request with invalid signature")
+				respondForbidden(w, "Invalid or expired URL signature", err.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func APIKeyMiddleware(key string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided := r.Header.Get(APIKeyHeader)
+			if key == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(key)) != 1 {
+				zlog.Logger.Warn().
+					Str("path", r.URL.Path).
+					Msg("Rejected request with invalid API key")
+				respondForbidden(w, "Invalid or missing API key", "")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func respondForbidden(w http.ResponseWriter, message, details string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(dto.ErrorResponse{
+		Error:   http.StatusText(http.StatusForbidden),
+		Message: message,
+		Details: details,
+	})
+}
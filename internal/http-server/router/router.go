@@ -8,12 +8,15 @@ import (
 
 	"image-processor/internal/http-server/handler/image"
 	"image-processor/internal/http-server/middleware"
+	"image-processor/internal/http-server/signature"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type Handler struct {
 	ImageHandler *image.ImageHandler
+	Signer       *signature.Signer
+	APIKey       string
 }
 
 func SetupRouter(h *Handler) http.Handler {
@@ -42,9 +45,33 @@ func SetupRouter(h *Handler) http.Handler {
 			r.Get("/", h.ImageHandler.ListImages)
 			r.Post("/upload", h.ImageHandler.UploadImage)
 			r.Get("/{id}", h.ImageHandler.GetImage)
+			r.With(middleware.SignatureMiddleware(h.Signer)).Get("/{id}/transform", h.ImageHandler.TransformImage)
+			r.With(middleware.APIKeyMiddleware(h.APIKey)).Post("/{id}/transform/sign", h.ImageHandler.SignTransformURL)
 			r.Get("/{id}/status", h.ImageHandler.GetStatus)
+			r.Get("/{id}/metadata", h.ImageHandler.GetMetadata)
+			r.Get("/{id}/srcset", h.ImageHandler.GetSrcset)
 			r.Delete("/{id}", h.ImageHandler.DeleteImage)
 		})
+		r.Route("/presets", func(r chi.Router) {
+			r.Get("/", h.ImageHandler.ListPresets)
+			r.Post("/", h.ImageHandler.CreatePreset)
+			r.Get("/{name}", h.ImageHandler.GetPreset)
+			r.Put("/{name}", h.ImageHandler.UpdatePreset)
+			r.Delete("/{name}", h.ImageHandler.DeletePreset)
+		})
+		r.Route("/watermarks", func(r chi.Router) {
+			r.Get("/", h.ImageHandler.ListWatermarks)
+			r.Post("/", h.ImageHandler.UploadWatermark)
+			r.Get("/{id}", h.ImageHandler.GetWatermark)
+			r.Delete("/{id}", h.ImageHandler.DeleteWatermark)
+		})
+		r.Route("/fonts", func(r chi.Router) {
+			r.Get("/", h.ImageHandler.ListFonts)
+			r.Post("/", h.ImageHandler.UploadFont)
+			r.Get("/{id}", h.ImageHandler.GetFont)
+			r.Delete("/{id}", h.ImageHandler.DeleteFont)
+		})
+		r.Get("/operations", h.ImageHandler.ListOperations)
 		r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 			w.Write([]byte(`{"status":"ok"}`))
 		})
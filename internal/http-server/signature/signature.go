@@ -0,0 +1,86 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	ParamSignature = "sig"
+	ParamExpires   = "expires"
+)
+
+var (
+	ErrMissingSignature = errors.New("missing signature")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrExpired          = errors.New("signature expired")
+)
+
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewSigner(secret string, ttl time.Duration) *Signer {
+	return &Signer{
+		secret: []byte(secret),
+		ttl:    ttl,
+	}
+}
+
+func (s *Signer) Enabled() bool {
+	return len(s.secret) > 0
+}
+
+func (s *Signer) DefaultTTL() time.Duration {
+	return s.ttl
+}
+
+func (s *Signer) Sign(path string, query url.Values, expiresAt time.Time) string {
+	signed := cloneValues(query)
+	signed.Del(ParamSignature)
+	signed.Set(ParamExpires, strconv.FormatInt(expiresAt.Unix(), 10))
+	signed.Set(ParamSignature, s.signature(path, signed))
+	return path + "?" + signed.Encode()
+}
+
+func (s *Signer) Verify(path string, query url.Values, now time.Time) error {
+	provided := query.Get(ParamSignature)
+	if provided == "" {
+		return ErrMissingSignature
+	}
+	expires, err := strconv.ParseInt(query.Get(ParamExpires), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	unsigned := cloneValues(query)
+	unsigned.Del(ParamSignature)
+	if !hmac.Equal([]byte(provided), []byte(s.signature(path, unsigned))) {
+		return ErrInvalidSignature
+	}
+	if now.Unix() > expires {
+		return ErrExpired
+	}
+	return nil
+}
+
+func (s *Signer) signature(path string, query url.Values) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(path))
+	mac.Write([]byte{'?'})
+	mac.Write([]byte(query.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func cloneValues(values url.Values) url.Values {
+	cloned := make(url.Values, len(values))
+	for key, list := range values {
+		cloned[key] = append([]string(nil), list...)
+	}
+	return cloned
+}
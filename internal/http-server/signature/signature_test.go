@@ -0,0 +1,143 @@
+package signature
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSignerVerify(t *testing.T) {
+	signer := NewSigner("secret", time.Hour)
+	now := time.Unix(1700000000, 0)
+	const path = "/api/images/550e8400-e29b-41d4-a716-446655440000/transform"
+	signed := func(t *testing.T, query url.Values, expiresAt time.Time) url.Values {
+		t.Helper()
+		parsed, err := url.Parse(signer.Sign(path, query, expiresAt))
+		if err != nil {
+			t.Fatalf("failed to parse signed url: %v", err)
+		}
+		return parsed.Query()
+	}
+	tests := []struct {
+		name   string
+		path   string
+		query  func(t *testing.T) url.Values
+		now    time.Time
+		expect error
+	}{
+		{
+			name: "valid",
+			path: path,
+			query: func(t *testing.T) url.Values {
+				return signed(t, url.Values{"w": {"300"}, "h": {"200"}}, now.Add(time.Minute))
+			},
+			now: now,
+		},
+		{
+			name: "parameter order does not matter",
+			path: path,
+			query: func(t *testing.T) url.Values {
+				query := signed(t, url.Values{"w": {"300"}, "fit": {"cover"}}, now.Add(time.Minute))
+				reordered := url.Values{}
+				for _, key := range []string{ParamSignature, "fit", ParamExpires, "w"} {
+					reordered[key] = query[key]
+				}
+				return reordered
+			},
+			now: now,
+		},
+		{
+			name:   "missing signature",
+			path:   path,
+			query:  func(t *testing.T) url.Values { return url.Values{"w": {"300"}, ParamExpires: {"1700000060"}} },
+			now:    now,
+			expect: ErrMissingSignature,
+		},
+		{
+			name: "missing expiry",
+			path: path,
+			query: func(t *testing.T) url.Values {
+				query := signed(t, url.Values{"w": {"300"}}, now.Add(time.Minute))
+				query.Del(ParamExpires)
+				return query
+			},
+			now:    now,
+			expect: ErrInvalidSignature,
+		},
+		{
+			name: "tampered parameter",
+			path: path,
+			query: func(t *testing.T) url.Values {
+				query := signed(t, url.Values{"w": {"300"}}, now.Add(time.Minute))
+				query.Set("w", "3000")
+				return query
+			},
+			now:    now,
+			expect: ErrInvalidSignature,
+		},
+		{
+			name: "added parameter",
+			path: path,
+			query: func(t *testing.T) url.Values {
+				query := signed(t, url.Values{"w": {"300"}}, now.Add(time.Minute))
+				query.Set("q", "100")
+				return query
+			},
+			now:    now,
+			expect: ErrInvalidSignature,
+		},
+		{
+			name: "extended expiry",
+			path: path,
+			query: func(t *testing.T) url.Values {
+				query := signed(t, url.Values{"w": {"300"}}, now.Add(time.Minute))
+				query.Set(ParamExpires, "1800000000")
+				return query
+			},
+			now:    now,
+			expect: ErrInvalidSignature,
+		},
+		{
+			name:   "other image",
+			path:   "/api/images/00000000-0000-0000-0000-000000000000/transform",
+			query:  func(t *testing.T) url.Values { return signed(t, url.Values{"w": {"300"}}, now.Add(time.Minute)) },
+			now:    now,
+			expect: ErrInvalidSignature,
+		},
+		{
+			name:   "expired",
+			path:   path,
+			query:  func(t *testing.T) url.Values { return signed(t, url.Values{"w": {"300"}}, now.Add(-time.Second)) },
+			now:    now,
+			expect: ErrExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := signer.Verify(tt.path, tt.query(t), tt.now)
+			if !errors.Is(err, tt.expect) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.expect)
+			}
+		})
+	}
+}
+
+func TestSignerVerifyRejectsOtherSecret(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	signedURL, err := url.Parse(NewSigner("other", time.Hour).Sign("/api/images/1/transform", url.Values{"w": {"300"}}, now.Add(time.Minute)))
+	if err != nil {
+		t.Fatalf("failed to parse signed url: %v", err)
+	}
+	if err := NewSigner("secret", time.Hour).Verify(signedURL.Path, signedURL.Query(), now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestSignDoesNotMutateQuery(t *testing.T) {
+	query := url.Values{"w": {"300"}, ParamSignature: {"stale"}}
+	NewSigner("secret", time.Hour).Sign("/p", query, time.Unix(1700000000, 0))
+	if query.Get(ParamSignature) != "stale" || query.Has(ParamExpires) {
+		t.Errorf("Sign() modified the input query: %v", query)
+	}
+}
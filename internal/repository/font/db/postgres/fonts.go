@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/repository/font"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const fontColumns = `id, filename, family, format, size, path, created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type FontsRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewFontsRepository(db *dbpg.DB, retries retry.Strategy) *FontsRepository {
+	return &FontsRepository{
+		db:      db,
+		retries: retries,
+	}
+}
+
+func (r *FontsRepository) Create(ctx context.Context, asset *domain.FontAsset) error {
+	query := `
+	INSERT INTO fonts (` + fontColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	asset.CreatedAt = time.Now()
+	_, err := r.db.ExecWithRetry(ctx, r.retries, query,
+		asset.ID,
+		asset.Filename,
+		asset.Family,
+		asset.Format,
+		asset.Size,
+		asset.Path,
+		asset.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save font: %w", err)
+	}
+	return nil
+}
+
+func (r *FontsRepository) GetByID(ctx context.Context, id string) (*domain.FontAsset, error) {
+	query := `SELECT ` + fontColumns + ` FROM fonts WHERE id = $1`
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query font: %w", err)
+	}
+	asset, err := scanFont(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan font: %w", err)
+	}
+	return asset, nil
+}
+
+func (r *FontsRepository) List(ctx context.Context) ([]domain.FontAsset, error) {
+	query := `SELECT ` + fontColumns + ` FROM fonts ORDER BY family, created_at DESC`
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fonts: %w", err)
+	}
+	defer rows.Close()
+	var assets []domain.FontAsset
+	for rows.Next() {
+		asset, err := scanFont(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan font: %w", err)
+		}
+		assets = append(assets, *asset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating fonts: %w", err)
+	}
+	return assets, nil
+}
+
+func (r *FontsRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM fonts WHERE id = $1`
+	result, err := r.db.ExecWithRetry(ctx, r.retries, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete font: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return font.ErrFontNotFound
+	}
+	return nil
+}
+
+func scanFont(row rowScanner) (*domain.FontAsset, error) {
+	var asset domain.FontAsset
+	err := row.Scan(
+		&asset.ID,
+		&asset.Filename,
+		&asset.Family,
+		&asset.Format,
+		&asset.Size,
+		&asset.Path,
+		&asset.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
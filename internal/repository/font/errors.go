@@ -0,0 +1,5 @@
+package font
+
+import "errors"
+
+var ErrFontNotFound = errors.New("font not found")
@@ -116,6 +116,21 @@ func (r *FileRepository) GetObject(ctx context.Context, path string) (io.ReadClo
 	return obj, nil
 }
 
+func (r *FileRepository) ObjectExists(ctx context.Context, path string) (bool, error) {
+	safePath, err := sanitizePath(path)
+	if err != nil {
+		return false, fmt.Errorf("invalid path: %w", err)
+	}
+	_, err = r.client.StatObject(ctx, r.cfg.MinIO.Bucket, safePath, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to stat object: %w", err)
+	}
+	return true, nil
+}
+
 func (r *FileRepository) SaveProcessed(ctx context.Context, path string, data io.Reader, size int64, contentType string) error {
 	safePath, err := sanitizePath(path)
 	if err != nil {
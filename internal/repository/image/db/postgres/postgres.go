@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -14,6 +15,18 @@ import (
 	"github.com/wb-go/wbf/retry"
 )
 
+const imageColumns = `id, original_filename, original_size, mime_type,
+		status, original_path, bucket, width, height, color_model, bit_depth,
+		frame_count, orientation, blurhash, thumbhash, average_color, palette, exif, metadata_report,
+		created_at, updated_at`
+
+const processedColumns = `id, image_id, operation, parameters, variant, steps, cache_key,
+		preset, preset_version, crop_box, path, size, mime_type, format, status, created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
 type ImagesRepository struct {
 	db      *dbpg.DB
 	retries retry.Strategy
@@ -52,8 +65,7 @@ func (r *ImagesRepository) Save(ctx context.Context, img *domain.Image) error {
 
 func (r *ImagesRepository) GetByID(ctx context.Context, id string) (*domain.Image, error) {
 	query := `
-	SELECT id, original_filename, original_size, mime_type,
-		status, original_path, bucket, created_at, updated_at
+	SELECT ` + imageColumns + `
 	FROM images
 	WHERE id = $1 AND status != $2
 	`
@@ -61,25 +73,14 @@ func (r *ImagesRepository) GetByID(ctx context.Context, id string) (*domain.Imag
 	if err != nil {
 		return nil, fmt.Errorf("failed to query image: %w", err)
 	}
-	var img domain.Image
-	err = row.Scan(
-		&img.ID,
-		&img.OriginalFilename,
-		&img.OriginalSize,
-		&img.MimeType,
-		&img.Status,
-		&img.OriginalPath,
-		&img.Bucket,
-		&img.CreatedAt,
-		&img.UpdatedAt,
-	)
+	img, err := scanImage(row)
 	if err == sql.ErrNoRows {
 		return nil, image.ErrImageNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan image: %w", err)
 	}
-	return &img, nil
+	return img, nil
 }
 
 func (r *ImagesRepository) UpdateStatus(ctx context.Context, id string, status domain.ImageStatus) error {
@@ -98,6 +99,22 @@ func (r *ImagesRepository) UpdateStatus(ctx context.Context, id string, status d
 	return nil
 }
 
+func (r *ImagesRepository) UpdateOriginal(ctx context.Context, id, path string, size int64, mimeType string) error {
+	query := `UPDATE images SET original_path = $1, original_size = $2, mime_type = $3, updated_at = $4 WHERE id = $5`
+	result, err := r.db.ExecWithRetry(ctx, r.retries, query, path, size, mimeType, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update original: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return image.ErrImageNotFound
+	}
+	return nil
+}
+
 func (r *ImagesRepository) Update(ctx context.Context, img *domain.Image) error {
 	query := `UPDATE images SET status = $1, updated_at = $2 WHERE id = $3`
 	img.UpdatedAt = time.Now()
@@ -115,6 +132,78 @@ func (r *ImagesRepository) Update(ctx context.Context, img *domain.Image) error
 	return nil
 }
 
+func (r *ImagesRepository) UpdateMetadata(ctx context.Context, id string, metadata domain.ImageMetadata) error {
+	var exifJSON sql.NullString
+	if metadata.Exif != nil {
+		data, err := json.Marshal(metadata.Exif)
+		if err != nil {
+			return fmt.Errorf("failed to marshal exif: %w", err)
+		}
+		exifJSON = sql.NullString{String: string(data), Valid: true}
+	}
+	var paletteJSON sql.NullString
+	if metadata.Palette != nil {
+		data, err := json.Marshal(metadata.Palette)
+		if err != nil {
+			return fmt.Errorf("failed to marshal palette: %w", err)
+		}
+		paletteJSON = sql.NullString{String: string(data), Valid: true}
+	}
+	var reportJSON sql.NullString
+	var originalSize sql.NullInt64
+	var mimeType sql.NullString
+	if metadata.Report != nil {
+		data, err := json.Marshal(metadata.Report)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata report: %w", err)
+		}
+		reportJSON = sql.NullString{String: string(data), Valid: true}
+		if (metadata.Report.OriginalScrubbed || metadata.Report.OriginalRedacted) && metadata.Report.OriginalSize > 0 {
+			originalSize = sql.NullInt64{Int64: metadata.Report.OriginalSize, Valid: true}
+		}
+		if metadata.Report.OriginalMimeType != "" {
+			mimeType = sql.NullString{String: metadata.Report.OriginalMimeType, Valid: true}
+		}
+	}
+	query := `
+	UPDATE images SET
+		width = $1, height = $2, color_model = $3, bit_depth = $4,
+		frame_count = $5, orientation = $6, blurhash = $7, thumbhash = $8, average_color = $9, palette = $10,
+		exif = $11, metadata_report = $12, original_size = COALESCE($13, original_size),
+		mime_type = COALESCE($14, mime_type), updated_at = $15
+	WHERE id = $16
+	`
+	result, err := r.db.ExecWithRetry(ctx, r.retries, query,
+		metadata.Width,
+		metadata.Height,
+		metadata.ColorModel,
+		metadata.BitDepth,
+		metadata.FrameCount,
+		metadata.Orientation,
+		metadata.BlurHash,
+		metadata.ThumbHash,
+		metadata.AverageColor,
+		paletteJSON,
+		exifJSON,
+		reportJSON,
+		originalSize,
+		mimeType,
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update metadata: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return image.ErrImageNotFound
+	}
+	return nil
+}
+
 func (r *ImagesRepository) Delete(ctx context.Context, id string) error {
 	query := `UPDATE images SET status = $1, updated_at = $2 WHERE id = $3`
 	result, err := r.db.ExecWithRetry(ctx, r.retries, query, domain.StatusDeleted, time.Now(), id)
@@ -134,10 +223,21 @@ func (r *ImagesRepository) Delete(ctx context.Context, id string) error {
 func (r *ImagesRepository) SaveProcessedImage(ctx context.Context, processed *domain.ProcessedImage) error {
 	query := `
 	INSERT INTO processed_images (
-	id, image_id, operation, parameters, path,
-	size, mime_type, format, status, created_at
-	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	id, image_id, operation, parameters, variant, steps, cache_key,
+	preset, preset_version, crop_box, path, size, mime_type, format, status, created_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	ON CONFLICT (image_id, cache_key) WHERE cache_key <> '' DO UPDATE SET
+		path = EXCLUDED.path, size = EXCLUDED.size, mime_type = EXCLUDED.mime_type,
+		format = EXCLUDED.format, crop_box = EXCLUDED.crop_box, status = EXCLUDED.status
 	`
+	var cropJSON sql.NullString
+	if processed.CropBox != nil {
+		data, err := json.Marshal(processed.CropBox)
+		if err != nil {
+			return fmt.Errorf("failed to marshal crop box: %w", err)
+		}
+		cropJSON = sql.NullString{String: string(data), Valid: true}
+	}
 	processed.ID = uuid.New().String()
 	processed.CreatedAt = time.Now()
 	_, err := r.db.ExecWithRetry(ctx, r.retries, query,
@@ -145,6 +245,12 @@ func (r *ImagesRepository) SaveProcessedImage(ctx context.Context, processed *do
 		processed.ImageID,
 		processed.Operation,
 		processed.Parameters,
+		processed.Variant,
+		processed.Steps,
+		processed.CacheKey,
+		processed.Preset,
+		processed.PresetVersion,
+		cropJSON,
 		processed.Path,
 		processed.Size,
 		processed.MimeType,
@@ -160,8 +266,7 @@ func (r *ImagesRepository) SaveProcessedImage(ctx context.Context, processed *do
 
 func (r *ImagesRepository) GetProcessedImages(ctx context.Context, imageID string) ([]domain.ProcessedImage, error) {
 	query := `
-	SELECT id, image_id, operation, parameters, path,
-		size, mime_type, format, status, created_at
+	SELECT ` + processedColumns + `
 	FROM processed_images
 	WHERE image_id = $1
 	ORDER BY created_at DESC
@@ -173,23 +278,11 @@ func (r *ImagesRepository) GetProcessedImages(ctx context.Context, imageID strin
 	defer rows.Close()
 	var processed []domain.ProcessedImage
 	for rows.Next() {
-		var p domain.ProcessedImage
-		err := rows.Scan(
-			&p.ID,
-			&p.ImageID,
-			&p.Operation,
-			&p.Parameters,
-			&p.Path,
-			&p.Size,
-			&p.MimeType,
-			&p.Format,
-			&p.Status,
-			&p.CreatedAt,
-		)
+		p, err := scanProcessedImage(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan processed image: %w", err)
 		}
-		processed = append(processed, p)
+		processed = append(processed, *p)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating processed images: %w", err)
@@ -199,36 +292,66 @@ func (r *ImagesRepository) GetProcessedImages(ctx context.Context, imageID strin
 
 func (r *ImagesRepository) GetProcessedImageByOperation(ctx context.Context, imageID, operation string) (*domain.ProcessedImage, error) {
 	query := `
-	SELECT id, image_id, operation, parameters, path,
-		size, mime_type, format, status, created_at
+	SELECT ` + processedColumns + `
 	FROM processed_images
-	WHERE image_id = $1 AND operation = $2
+	WHERE image_id = $1 AND operation = $2 AND variant = ''
+	ORDER BY created_at
 	LIMIT 1
 	`
 	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, imageID, operation)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query processed image: %w", err)
 	}
-	var processed domain.ProcessedImage
-	err = row.Scan(
-		&processed.ID,
-		&processed.ImageID,
-		&processed.Operation,
-		&processed.Parameters,
-		&processed.Path,
-		&processed.Size,
-		&processed.MimeType,
-		&processed.Format,
-		&processed.Status,
-		&processed.CreatedAt,
-	)
+	processed, err := scanProcessedImage(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan processed image: %w", err)
 	}
-	return &processed, nil
+	return processed, nil
+}
+
+func (r *ImagesRepository) GetProcessedImageByVariant(ctx context.Context, imageID, variant string) (*domain.ProcessedImage, error) {
+	query := `
+	SELECT ` + processedColumns + `
+	FROM processed_images
+	WHERE image_id = $1 AND variant = $2
+	LIMIT 1
+	`
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, imageID, variant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query processed image: %w", err)
+	}
+	processed, err := scanProcessedImage(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan processed image: %w", err)
+	}
+	return processed, nil
+}
+
+func (r *ImagesRepository) GetProcessedImageByCacheKey(ctx context.Context, imageID, cacheKey string) (*domain.ProcessedImage, error) {
+	query := `
+	SELECT ` + processedColumns + `
+	FROM processed_images
+	WHERE image_id = $1 AND cache_key = $2
+	LIMIT 1
+	`
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, imageID, cacheKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query processed image: %w", err)
+	}
+	processed, err := scanProcessedImage(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan processed image: %w", err)
+	}
+	return processed, nil
 }
 
 func (r *ImagesRepository) DeleteProcessedImages(ctx context.Context, imageID string) error {
@@ -244,38 +367,38 @@ func (r *ImagesRepository) DeleteProcessedImages(ctx context.Context, imageID st
 	return nil
 }
 
-func (r *ImagesRepository) List(ctx context.Context, limit, offset int) ([]domain.Image, error) {
+func (r *ImagesRepository) List(ctx context.Context, limit, offset int, filter *domain.ColorFilter) ([]domain.Image, error) {
 	query := `
-	SELECT id, original_filename, original_size, mime_type,
-		status, original_path, bucket, created_at, updated_at
+	SELECT ` + imageColumns + `
 	FROM images
 	WHERE status != $1
 	ORDER BY created_at DESC
 	LIMIT $2 OFFSET $3
 	`
-	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, domain.StatusDeleted, limit, offset)
+	args := []interface{}{domain.StatusDeleted, limit, offset}
+	if filter != nil {
+		query = `
+		SELECT ` + imageColumns + `
+		FROM images
+		` + colorMatchJoin(4) + `
+		WHERE status != $1 AND color_match.distance <= $8
+		ORDER BY color_match.distance, created_at DESC
+		LIMIT $2 OFFSET $3
+		`
+		args = append(args, filter.Lab[0], filter.Lab[1], filter.Lab[2], filter.MinWeight, filter.MaxDistance)
+	}
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query images: %w", err)
 	}
 	defer rows.Close()
 	var images []domain.Image
 	for rows.Next() {
-		var img domain.Image
-		err := rows.Scan(
-			&img.ID,
-			&img.OriginalFilename,
-			&img.OriginalSize,
-			&img.MimeType,
-			&img.Status,
-			&img.OriginalPath,
-			&img.Bucket,
-			&img.CreatedAt,
-			&img.UpdatedAt,
-		)
+		img, err := scanImage(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan image: %w", err)
 		}
-		images = append(images, img)
+		images = append(images, *img)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating images: %w", err)
@@ -283,9 +406,19 @@ func (r *ImagesRepository) List(ctx context.Context, limit, offset int) ([]domai
 	return images, nil
 }
 
-func (r *ImagesRepository) Count(ctx context.Context) (int, error) {
+func (r *ImagesRepository) Count(ctx context.Context, filter *domain.ColorFilter) (int, error) {
 	query := `SELECT COUNT(*) FROM images WHERE status != $1`
-	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, domain.StatusDeleted)
+	args := []interface{}{domain.StatusDeleted}
+	if filter != nil {
+		query = `
+		SELECT COUNT(*)
+		FROM images
+		` + colorMatchJoin(2) + `
+		WHERE status != $1 AND color_match.distance <= $6
+		`
+		args = append(args, filter.Lab[0], filter.Lab[1], filter.Lab[2], filter.MinWeight, filter.MaxDistance)
+	}
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, args...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count images: %w", err)
 	}
@@ -296,3 +429,106 @@ func (r *ImagesRepository) Count(ctx context.Context) (int, error) {
 	}
 	return count, nil
 }
+
+func colorMatchJoin(first int) string {
+	return fmt.Sprintf(`CROSS JOIN LATERAL (
+			SELECT MIN(SQRT(
+				POWER((c->'lab'->>0)::float8 - $%d, 2) +
+				POWER((c->'lab'->>1)::float8 - $%d, 2) +
+				POWER((c->'lab'->>2)::float8 - $%d, 2)
+			)) AS distance
+			FROM jsonb_array_elements(images.palette) AS c
+			WHERE (c->>'weight')::float8 >= $%d
+		) AS color_match`, first, first+1, first+2, first+3)
+}
+
+func scanImage(row rowScanner) (*domain.Image, error) {
+	var (
+		img         domain.Image
+		paletteJSON []byte
+		exifJSON    []byte
+		reportJSON  []byte
+	)
+	err := row.Scan(
+		&img.ID,
+		&img.OriginalFilename,
+		&img.OriginalSize,
+		&img.MimeType,
+		&img.Status,
+		&img.OriginalPath,
+		&img.Bucket,
+		&img.Metadata.Width,
+		&img.Metadata.Height,
+		&img.Metadata.ColorModel,
+		&img.Metadata.BitDepth,
+		&img.Metadata.FrameCount,
+		&img.Metadata.Orientation,
+		&img.Metadata.BlurHash,
+		&img.Metadata.ThumbHash,
+		&img.Metadata.AverageColor,
+		&paletteJSON,
+		&exifJSON,
+		&reportJSON,
+		&img.CreatedAt,
+		&img.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(paletteJSON) > 0 {
+		if err := json.Unmarshal(paletteJSON, &img.Metadata.Palette); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal palette: %w", err)
+		}
+	}
+	if len(exifJSON) > 0 {
+		var exif domain.ExifMetadata
+		if err := json.Unmarshal(exifJSON, &exif); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal exif: %w", err)
+		}
+		img.Metadata.Exif = &exif
+	}
+	if len(reportJSON) > 0 {
+		var report domain.MetadataReport
+		if err := json.Unmarshal(reportJSON, &report); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata report: %w", err)
+		}
+		img.Metadata.Report = &report
+	}
+	return &img, nil
+}
+
+func scanProcessedImage(row rowScanner) (*domain.ProcessedImage, error) {
+	var (
+		processed domain.ProcessedImage
+		cropJSON  []byte
+	)
+	err := row.Scan(
+		&processed.ID,
+		&processed.ImageID,
+		&processed.Operation,
+		&processed.Parameters,
+		&processed.Variant,
+		&processed.Steps,
+		&processed.CacheKey,
+		&processed.Preset,
+		&processed.PresetVersion,
+		&cropJSON,
+		&processed.Path,
+		&processed.Size,
+		&processed.MimeType,
+		&processed.Format,
+		&processed.Status,
+		&processed.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(cropJSON) > 0 {
+		var box domain.CropBox
+		if err := json.Unmarshal(cropJSON, &box); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal crop box: %w", err)
+		}
+		processed.CropBox = &box
+	}
+	return &processed, nil
+}
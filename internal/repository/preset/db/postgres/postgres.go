@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/repository/preset"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const presetColumns = `name, description, pipeline, operations, version, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type PresetsRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewPresetsRepository(db *dbpg.DB, retries retry.Strategy) *PresetsRepository {
+	return &PresetsRepository{
+		db:      db,
+		retries: retries,
+	}
+}
+
+func (r *PresetsRepository) Create(ctx context.Context, p *domain.Preset) error {
+	operations, err := json.Marshal(p.Operations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preset operations: %w", err)
+	}
+	query := `
+	INSERT INTO presets (name, description, pipeline, operations, version, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (name) DO NOTHING
+	`
+	p.Version = 1
+	p.CreatedAt = time.Now()
+	p.UpdatedAt = p.CreatedAt
+	result, err := r.db.ExecWithRetry(ctx, r.retries, query,
+		p.Name,
+		p.Description,
+		p.Pipeline,
+		string(operations),
+		p.Version,
+		p.CreatedAt,
+		p.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save preset: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return preset.ErrPresetExists
+	}
+	return nil
+}
+
+func (r *PresetsRepository) GetByName(ctx context.Context, name string) (*domain.Preset, error) {
+	query := `SELECT ` + presetColumns + ` FROM presets WHERE name = $1`
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query preset: %w", err)
+	}
+	p, err := scanPreset(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan preset: %w", err)
+	}
+	return p, nil
+}
+
+func (r *PresetsRepository) List(ctx context.Context) ([]domain.Preset, error) {
+	query := `SELECT ` + presetColumns + ` FROM presets ORDER BY name`
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query presets: %w", err)
+	}
+	defer rows.Close()
+	var presets []domain.Preset
+	for rows.Next() {
+		p, err := scanPreset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan preset: %w", err)
+		}
+		presets = append(presets, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating presets: %w", err)
+	}
+	return presets, nil
+}
+
+func (r *PresetsRepository) Update(ctx context.Context, p *domain.Preset) error {
+	operations, err := json.Marshal(p.Operations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preset operations: %w", err)
+	}
+	query := `
+	UPDATE presets SET
+		description = $1, pipeline = $2, operations = $3,
+		version = version + 1, updated_at = $4
+	WHERE name = $5
+	RETURNING version, created_at
+	`
+	p.UpdatedAt = time.Now()
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query,
+		p.Description,
+		p.Pipeline,
+		string(operations),
+		p.UpdatedAt,
+		p.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update preset: %w", err)
+	}
+	err = row.Scan(&p.Version, &p.CreatedAt)
+	if err == sql.ErrNoRows {
+		return preset.ErrPresetNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to scan updated preset: %w", err)
+	}
+	return nil
+}
+
+func (r *PresetsRepository) Delete(ctx context.Context, name string) error {
+	query := `DELETE FROM presets WHERE name = $1`
+	result, err := r.db.ExecWithRetry(ctx, r.retries, query, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete preset: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return preset.ErrPresetNotFound
+	}
+	return nil
+}
+
+func scanPreset(row rowScanner) (*domain.Preset, error) {
+	var (
+		p              domain.Preset
+		operationsJSON []byte
+	)
+	err := row.Scan(
+		&p.Name,
+		&p.Description,
+		&p.Pipeline,
+		&operationsJSON,
+		&p.Version,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(operationsJSON, &p.Operations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal preset operations: %w", err)
+	}
+	return &p, nil
+}
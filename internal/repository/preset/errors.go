@@ -0,0 +1,8 @@
+package preset
+
+import "errors"
+
+var (
+	ErrPresetNotFound = errors.New("preset not found")
+	ErrPresetExists   = errors.New("preset already exists")
+)
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/repository/watermark"
+
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
+)
+
+const watermarkColumns = `id, filename, mime_type, format, width, height, size, path, created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type WatermarksRepository struct {
+	db      *dbpg.DB
+	retries retry.Strategy
+}
+
+func NewWatermarksRepository(db *dbpg.DB, retries retry.Strategy) *WatermarksRepository {
+	return &WatermarksRepository{
+		db:      db,
+		retries: retries,
+	}
+}
+
+func (r *WatermarksRepository) Create(ctx context.Context, asset *domain.WatermarkAsset) error {
+	query := `
+	INSERT INTO watermarks (` + watermarkColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	asset.CreatedAt = time.Now()
+	_, err := r.db.ExecWithRetry(ctx, r.retries, query,
+		asset.ID,
+		asset.Filename,
+		asset.MimeType,
+		asset.Format,
+		asset.Width,
+		asset.Height,
+		asset.Size,
+		asset.Path,
+		asset.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save watermark: %w", err)
+	}
+	return nil
+}
+
+func (r *WatermarksRepository) GetByID(ctx context.Context, id string) (*domain.WatermarkAsset, error) {
+	query := `SELECT ` + watermarkColumns + ` FROM watermarks WHERE id = $1`
+	row, err := r.db.QueryRowWithRetry(ctx, r.retries, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watermark: %w", err)
+	}
+	asset, err := scanWatermark(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan watermark: %w", err)
+	}
+	return asset, nil
+}
+
+func (r *WatermarksRepository) List(ctx context.Context) ([]domain.WatermarkAsset, error) {
+	query := `SELECT ` + watermarkColumns + ` FROM watermarks ORDER BY created_at DESC`
+	rows, err := r.db.QueryWithRetry(ctx, r.retries, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watermarks: %w", err)
+	}
+	defer rows.Close()
+	var assets []domain.WatermarkAsset
+	for rows.Next() {
+		asset, err := scanWatermark(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan watermark: %w", err)
+		}
+		assets = append(assets, *asset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating watermarks: %w", err)
+	}
+	return assets, nil
+}
+
+func (r *WatermarksRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM watermarks WHERE id = $1`
+	result, err := r.db.ExecWithRetry(ctx, r.retries, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete watermark: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return watermark.ErrWatermarkNotFound
+	}
+	return nil
+}
+
+func scanWatermark(row rowScanner) (*domain.WatermarkAsset, error) {
+	var asset domain.WatermarkAsset
+	err := row.Scan(
+		&asset.ID,
+		&asset.Filename,
+		&asset.MimeType,
+		&asset.Format,
+		&asset.Width,
+		&asset.Height,
+		&asset.Size,
+		&asset.Path,
+		&asset.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
@@ -0,0 +1,5 @@
+package watermark
+
+import "errors"
+
+var ErrWatermarkNotFound = errors.New("watermark not found")
@@ -0,0 +1,21 @@
+package font
+
+import (
+	"context"
+	"io"
+
+	"image-processor/internal/domain"
+)
+
+type fontRepository interface {
+	Create(ctx context.Context, asset *domain.FontAsset) error
+	GetByID(ctx context.Context, id string) (*domain.FontAsset, error)
+	List(ctx context.Context) ([]domain.FontAsset, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type fileRepository interface {
+	GetObject(ctx context.Context, path string) (io.ReadCloser, error)
+	SaveProcessed(ctx context.Context, path string, data io.Reader, size int64, contentType string) error
+	DeleteObject(ctx context.Context, path string) error
+}
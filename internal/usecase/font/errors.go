@@ -0,0 +1,11 @@
+package font
+
+import "errors"
+
+var (
+	ErrFontNotFound      = errors.New("font not found")
+	ErrInvalidFileFormat = errors.New("invalid file format")
+	ErrFileTooLarge      = errors.New("file too large")
+	ErrStorageError      = errors.New("storage error")
+	ErrDatabaseError     = errors.New("database error")
+)
@@ -0,0 +1,141 @@
+package font
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"image-processor/internal/domain"
+	font_repo "image-processor/internal/repository/font"
+
+	"github.com/google/uuid"
+	"github.com/wb-go/wbf/zlog"
+	"golang.org/x/image/font/sfnt"
+)
+
+const maxFamilyLength = 255
+
+type FontUsecase struct {
+	repo     fontRepository
+	fileRepo fileRepository
+	logger   *zlog.Zerolog
+}
+
+func NewFontUsecase(repo fontRepository, fileRepo fileRepository, logger *zlog.Zerolog) *FontUsecase {
+	return &FontUsecase{
+		repo:     repo,
+		fileRepo: fileRepo,
+		logger:   logger,
+	}
+}
+
+func (u *FontUsecase) UploadFont(ctx context.Context, file io.Reader, filename string) (*domain.FontAsset, error) {
+	data, err := io.ReadAll(io.LimitReader(file, domain.MaxFontAssetSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font: %w", err)
+	}
+	if len(data) > domain.MaxFontAssetSize {
+		return nil, ErrFileTooLarge
+	}
+	format, mimeType, ok := detectFontFormat(data)
+	if !ok {
+		return nil, ErrInvalidFileFormat
+	}
+	parsed, err := sfnt.Parse(data)
+	if err != nil {
+		u.logger.Warn().Err(err).Str("filename", filename).Msg("Failed to parse font")
+		return nil, ErrInvalidFileFormat
+	}
+	id := uuid.New().String()
+	asset := &domain.FontAsset{
+		ID:       id,
+		Filename: filepath.Base(filename),
+		Family:   fontFamily(parsed, filename),
+		Format:   format,
+		Size:     int64(len(data)),
+		Path:     domain.FontAssetPath(id),
+	}
+	if err := u.fileRepo.SaveProcessed(ctx, asset.Path, bytes.NewReader(data), asset.Size, mimeType); err != nil {
+		u.logger.Error().Err(err).Str("font_id", id).Msg("Failed to save font to storage")
+		return nil, fmt.Errorf("%w: %v", ErrStorageError, err)
+	}
+	if err := u.repo.Create(ctx, asset); err != nil {
+		u.logger.Error().Err(err).Str("font_id", id).Msg("Failed to save font to DB")
+		if delErr := u.fileRepo.DeleteObject(ctx, asset.Path); delErr != nil {
+			u.logger.Error().Err(delErr).Str("path", asset.Path).Msg("Failed to cleanup font after DB error")
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	u.logger.Info().Str("font_id", id).Str("family", asset.Family).Msg("Font uploaded")
+	return asset, nil
+}
+
+func (u *FontUsecase) GetFont(ctx context.Context, id string) (*domain.FontAsset, io.ReadCloser, error) {
+	asset, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		u.logger.Error().Err(err).Str("font_id", id).Msg("Failed to get font")
+		return nil, nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if asset == nil {
+		return nil, nil, ErrFontNotFound
+	}
+	reader, err := u.fileRepo.GetObject(ctx, asset.Path)
+	if err != nil {
+		u.logger.Error().Err(err).Str("font_id", id).Str("path", asset.Path).Msg("Failed to get font from storage")
+		return nil, nil, fmt.Errorf("%w: %v", ErrStorageError, err)
+	}
+	return asset, reader, nil
+}
+
+func (u *FontUsecase) ListFonts(ctx context.Context) ([]domain.FontAsset, error) {
+	assets, err := u.repo.List(ctx)
+	if err != nil {
+		u.logger.Error().Err(err).Msg("Failed to list fonts")
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return assets, nil
+}
+
+func (u *FontUsecase) DeleteFont(ctx context.Context, id string) error {
+	if err := u.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, font_repo.ErrFontNotFound) {
+			return ErrFontNotFound
+		}
+		u.logger.Error().Err(err).Str("font_id", id).Msg("Failed to delete font")
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if err := u.fileRepo.DeleteObject(ctx, domain.FontAssetPath(id)); err != nil {
+		u.logger.Error().Err(err).Str("font_id", id).Msg("Failed to delete font from storage")
+	}
+	u.logger.Info().Str("font_id", id).Msg("Font deleted")
+	return nil
+}
+
+func detectFontFormat(header []byte) (domain.FontFormat, string, bool) {
+	if len(header) < 4 {
+		return "", "", false
+	}
+	switch string(header[:4]) {
+	case "\x00\x01\x00\x00", "true":
+		return domain.FontTTF, "font/ttf", true
+	case "OTTO":
+		return domain.FontOTF, "font/otf", true
+	default:
+		return "", "", false
+	}
+}
+
+func fontFamily(f *sfnt.Font, filename string) string {
+	family, err := f.Name(nil, sfnt.NameIDFamily)
+	if err != nil || strings.TrimSpace(family) == "" {
+		family = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	if runes := []rune(family); len(runes) > maxFamilyLength {
+		family = string(runes[:maxFamilyLength])
+	}
+	return family
+}
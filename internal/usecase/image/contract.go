@@ -17,9 +17,11 @@ type imageRepository interface {
 	SaveProcessedImage(ctx context.Context, processed *domain.ProcessedImage) error
 	GetProcessedImages(ctx context.Context, imageID string) ([]domain.ProcessedImage, error)
 	GetProcessedImageByOperation(ctx context.Context, imageID, operation string) (*domain.ProcessedImage, error)
+	GetProcessedImageByVariant(ctx context.Context, imageID, variant string) (*domain.ProcessedImage, error)
+	GetProcessedImageByCacheKey(ctx context.Context, imageID, cacheKey string) (*domain.ProcessedImage, error)
 	DeleteProcessedImages(ctx context.Context, imageID string) error
-	List(ctx context.Context, limit, offset int) ([]domain.Image, error)
-	Count(ctx context.Context) (int, error)
+	List(ctx context.Context, limit, offset int, filter *domain.ColorFilter) ([]domain.Image, error)
+	Count(ctx context.Context, filter *domain.ColorFilter) (int, error)
 }
 
 type fileRepository interface {
@@ -33,3 +35,15 @@ type fileRepository interface {
 type imageProducer interface {
 	broker.Producer
 }
+
+type presetRepository interface {
+	GetByName(ctx context.Context, name string) (*domain.Preset, error)
+}
+
+type imageTransformer interface {
+	Transform(ctx context.Context, task *domain.ProcessingTask, originalData []byte) (*domain.ProcessedVariant, []byte, error)
+}
+
+type formatDetector interface {
+	DetectFormat(header []byte) (domain.ImageFormat, string, bool)
+}
@@ -10,4 +10,8 @@ var (
 	ErrStorageError           = errors.New("storage error")
 	ErrDatabaseError          = errors.New("database error")
 	ErrMessageQueueError      = errors.New("message queue error")
+	ErrTransformFailed        = errors.New("transform failed")
+	ErrPresetNotFound         = errors.New("preset not found")
+	ErrReplacesOriginal       = errors.New("operations replace the original image")
+	ErrPresetNotPipeline      = errors.New("preset defines independent outputs")
 )
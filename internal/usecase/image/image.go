@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 	"time"
 
 	"image-processor/internal/domain"
@@ -18,39 +17,55 @@ import (
 )
 
 type ImageUsecase struct {
-	repo     imageRepository
-	fileRepo fileRepository
-	producer imageProducer
-	logger   *zlog.Zerolog
-	retries  retry.Strategy
+	repo        imageRepository
+	fileRepo    fileRepository
+	presets     presetRepository
+	producer    imageProducer
+	transformer imageTransformer
+	formats     formatDetector
+	logger      *zlog.Zerolog
+	retries     retry.Strategy
 }
 
-func NewImageUsecase(repo imageRepository, fileRepo fileRepository, producer imageProducer, logger *zlog.Zerolog, retries retry.Strategy) *ImageUsecase {
+func NewImageUsecase(repo imageRepository, fileRepo fileRepository, presets presetRepository, producer imageProducer, transformer imageTransformer, formats formatDetector, logger *zlog.Zerolog, retries retry.Strategy) *ImageUsecase {
 	return &ImageUsecase{
-		repo:     repo,
-		fileRepo: fileRepo,
-		producer: producer,
-		logger:   logger,
-		retries:  retries,
+		repo:        repo,
+		fileRepo:    fileRepo,
+		presets:     presets,
+		producer:    producer,
+		transformer: transformer,
+		formats:     formats,
+		logger:      logger,
+		retries:     retries,
 	}
 }
 
-func (i *ImageUsecase) UploadImage(ctx context.Context, file io.Reader, filename, contentType string, fileSize int64, operations []domain.OperationParams) (*domain.Image, error) {
+func (i *ImageUsecase) UploadImage(ctx context.Context, file io.Reader, filename, contentType string, fileSize int64, operations []domain.OperationParams, opts domain.ProcessingOptions) (*domain.Image, error) {
 	i.logger.Info().Str("filename", filename).Int64("size", fileSize).Msg("Starting image upload")
 	if fileSize > domain.DefaultMaxUploadSize {
 		i.logger.Warn().Str("filename", filename).Int64("size", fileSize).Msg("File too large")
 		return nil, fmt.Errorf("%w: max size is %d bytes", ErrFileTooLarge, domain.DefaultMaxUploadSize)
 	}
+	presetVersion := 0
+	if opts.Preset != "" {
+		preset, err := i.resolvePreset(ctx, opts.Preset)
+		if err != nil {
+			return nil, err
+		}
+		operations = preset.Operations
+		opts.Pipeline = preset.Pipeline
+		presetVersion = preset.Version
+	}
 	buf := make([]byte, 512)
 	n, err := io.ReadFull(file, buf)
 	if err != nil && err != io.ErrUnexpectedEOF {
 		i.logger.Error().Err(err).Str("filename", filename).Msg("Failed to read file header")
 		return nil, fmt.Errorf("failed to read file header: %w", err)
 	}
-	detectedType := http.DetectContentType(buf[:n])
-	if !strings.HasPrefix(detectedType, "image/") {
-		i.logger.Warn().Str("filename", filename).Str("detected_type", detectedType).Msg("Invalid file signature")
-		return nil, fmt.Errorf("%w: file is not an image", ErrInvalidFileFormat)
+	format, detectedType, ok := i.formats.DetectFormat(buf[:n])
+	if !ok {
+		i.logger.Warn().Str("filename", filename).Str("detected_type", http.DetectContentType(buf[:n])).Msg("Invalid file signature")
+		return nil, fmt.Errorf("%w: file is not a supported image", ErrInvalidFileFormat)
 	}
 	combinedReader := io.MultiReader(bytes.NewReader(buf[:n]), file)
 	imageID := uuid.New().String()
@@ -83,12 +98,17 @@ func (i *ImageUsecase) UploadImage(ctx context.Context, file io.Reader, filename
 		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
 	task := &domain.ProcessingTask{
-		ID:           uuid.New().String(),
-		ImageID:      imageID,
-		OriginalPath: originalPath,
-		Bucket:       "images",
-		Operations:   operations,
-		Format:       getFormatFromContentType(detectedType),
+		ID:            uuid.New().String(),
+		ImageID:       imageID,
+		OriginalPath:  originalPath,
+		Bucket:        "images",
+		Operations:    operations,
+		Format:        format,
+		Pipeline:      opts.Pipeline,
+		AutoOrient:    opts.AutoOrient,
+		Metadata:      opts.Metadata,
+		Preset:        opts.Preset,
+		PresetVersion: presetVersion,
 	}
 	taskBytes, err := json.Marshal(task)
 	if err != nil {
@@ -112,54 +132,197 @@ func (i *ImageUsecase) UploadImage(ctx context.Context, file io.Reader, filename
 	return img, nil
 }
 
-func (i *ImageUsecase) GetImage(ctx context.Context, id, operation string) (*domain.Image, io.ReadCloser, error) {
-	i.logger.Debug().Str("image_id", id).Str("operation", operation).Msg("Getting image")
+func (i *ImageUsecase) GetImage(ctx context.Context, id, operation, variant string) (*domain.Image, *domain.ProcessedImage, io.ReadCloser, error) {
+	i.logger.Debug().Str("image_id", id).Str("operation", operation).Str("variant", variant).Msg("Getting image")
 	img, err := i.repo.GetByID(ctx, id)
 	if err != nil {
 		if err == ErrImageNotFound {
 			i.logger.Info().Str("image_id", id).Msg("Image not found")
-			return nil, nil, ErrImageNotFound
+			return nil, nil, nil, ErrImageNotFound
 		}
 		i.logger.Error().Err(err).Str("image_id", id).Msg("Failed to get image from DB")
-		return nil, nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
-	if operation == "" {
+	if operation == "" && variant == "" {
 		reader, err := i.fileRepo.GetObject(ctx, img.OriginalPath)
 		if err != nil {
 			i.logger.Error().Err(err).Str("image_id", id).Str("path", img.OriginalPath).Msg("Failed to get original image from storage")
-			return nil, nil, fmt.Errorf("%w: %v", ErrStorageError, err)
+			return nil, nil, nil, fmt.Errorf("%w: %v", ErrStorageError, err)
 		}
-		return img, reader, nil
+		return img, nil, reader, nil
+	}
+	var processed *domain.ProcessedImage
+	if variant != "" {
+		processed, err = i.repo.GetProcessedImageByVariant(ctx, id, variant)
+	} else {
+		processed, err = i.repo.GetProcessedImageByOperation(ctx, id, operation)
 	}
-	processed, err := i.repo.GetProcessedImageByOperation(ctx, id, operation)
 	if err != nil {
-		i.logger.Error().Err(err).Str("image_id", id).Str("operation", operation).Msg("Failed to get processed image from DB")
-		return nil, nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		i.logger.Error().Err(err).Str("image_id", id).Str("operation", operation).Str("variant", variant).Msg("Failed to get processed image from DB")
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
 	if processed == nil {
-		i.logger.Info().Str("image_id", id).Str("operation", operation).Msg("Processed image not found")
-		return nil, nil, ErrProcessedImageNotFound
+		i.logger.Info().Str("image_id", id).Str("operation", operation).Str("variant", variant).Msg("Processed image not found")
+		return nil, nil, nil, ErrProcessedImageNotFound
 	}
 	reader, err := i.fileRepo.GetObject(ctx, processed.Path)
 	if err != nil {
 		i.logger.Error().Err(err).Str("image_id", id).Str("path", processed.Path).Msg("Failed to get processed image from storage")
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrStorageError, err)
+	}
+	return img, processed, reader, nil
+}
+
+func (i *ImageUsecase) TransformImage(ctx context.Context, id string, operations []domain.OperationParams, presetName string) (*domain.ProcessedImage, io.ReadCloser, error) {
+	var preset *domain.Preset
+	if presetName != "" {
+		var err error
+		if preset, err = i.resolvePreset(ctx, presetName); err != nil {
+			return nil, nil, err
+		}
+		if !preset.Pipeline && len(preset.Operations) > 1 {
+			i.logger.Info().Str("image_id", id).Str("preset", presetName).Msg("Rejected transform with a non-pipeline preset")
+			return nil, nil, fmt.Errorf("%w: %s", ErrPresetNotPipeline, presetName)
+		}
+		operations = preset.Operations
+	}
+	if domain.ReplacesOriginal(operations...) {
+		i.logger.Info().Str("image_id", id).Str("preset", presetName).Msg("Rejected transform that replaces the original")
+		return nil, nil, ErrReplacesOriginal
+	}
+	cacheKey := domain.TransformCacheKey(operations...)
+	i.logger.Debug().Str("image_id", id).Int("operations", len(operations)).Str("preset", presetName).Str("cache_key", cacheKey).Msg("Transforming image")
+	img, err := i.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == ErrImageNotFound {
+			i.logger.Info().Str("image_id", id).Msg("Image not found for transform")
+			return nil, nil, ErrImageNotFound
+		}
+		i.logger.Error().Err(err).Str("image_id", id).Msg("Failed to get image from DB")
+		return nil, nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	processed, err := i.repo.GetProcessedImageByCacheKey(ctx, id, cacheKey)
+	if err != nil {
+		i.logger.Error().Err(err).Str("image_id", id).Str("cache_key", cacheKey).Msg("Failed to look up cached variant")
+		return nil, nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if processed != nil {
+		reader, err := i.fileRepo.GetObject(ctx, processed.Path)
+		if err == nil {
+			return processed, reader, nil
+		}
+		i.logger.Warn().Err(err).Str("image_id", id).Str("path", processed.Path).Msg("Cached variant missing in storage, regenerating")
+	}
+	reader, err := i.fileRepo.GetObject(ctx, img.OriginalPath)
+	if err != nil {
+		i.logger.Error().Err(err).Str("image_id", id).Str("path", img.OriginalPath).Msg("Failed to get original image from storage")
+		return nil, nil, fmt.Errorf("%w: %v", ErrStorageError, err)
+	}
+	defer reader.Close()
+	originalData, err := io.ReadAll(reader)
+	if err != nil {
+		i.logger.Error().Err(err).Str("image_id", id).Str("path", img.OriginalPath).Msg("Failed to read original image")
 		return nil, nil, fmt.Errorf("%w: %v", ErrStorageError, err)
 	}
-	return img, reader, nil
+	task := &domain.ProcessingTask{
+		ID:           uuid.New().String(),
+		ImageID:      id,
+		OriginalPath: img.OriginalPath,
+		Bucket:       img.Bucket,
+		Operations:   operations,
+		AutoOrient:   true,
+	}
+	variant, data, err := i.transformer.Transform(ctx, task, originalData)
+	if err != nil {
+		i.logger.Warn().Err(err).Str("image_id", id).Str("cache_key", cacheKey).Msg("Transform failed")
+		return nil, nil, fmt.Errorf("%w: %v", ErrTransformFailed, err)
+	}
+	generated := &domain.ProcessedImage{
+		ImageID:    id,
+		Operation:  variant.Operation,
+		Parameters: variant.Parameters,
+		Steps:      variant.Steps,
+		CacheKey:   variant.CacheKey,
+		CropBox:    variant.CropBox,
+		Path:       variant.Path,
+		Size:       variant.Size,
+		MimeType:   variant.MimeType,
+		Format:     variant.Format,
+		Status:     "completed",
+	}
+	if preset != nil {
+		generated.Preset = preset.Name
+		generated.PresetVersion = preset.Version
+	}
+	if err := i.repo.SaveProcessedImage(ctx, generated); err != nil {
+		i.logger.Error().Err(err).Str("image_id", id).Str("cache_key", cacheKey).Msg("Failed to save transformed variant")
+	}
+	i.logger.Info().Str("image_id", id).Str("cache_key", cacheKey).Str("path", variant.Path).Int64("size", variant.Size).Msg("Transformed variant generated")
+	return generated, io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (i *ImageUsecase) resolvePreset(ctx context.Context, name string) (*domain.Preset, error) {
+	preset, err := i.presets.GetByName(ctx, name)
+	if err != nil {
+		i.logger.Error().Err(err).Str("preset", name).Msg("Failed to get preset from DB")
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if preset == nil {
+		i.logger.Info().Str("preset", name).Msg("Preset not found")
+		return nil, fmt.Errorf("%w: %s", ErrPresetNotFound, name)
+	}
+	return preset, nil
 }
 
-func (i *ImageUsecase) GetStatus(ctx context.Context, id string) (domain.ImageStatus, error) {
+func (i *ImageUsecase) GetStatus(ctx context.Context, id string) (*domain.Image, error) {
 	i.logger.Debug().Str("image_id", id).Msg("Getting image status")
 	img, err := i.repo.GetByID(ctx, id)
 	if err != nil {
 		if err == ErrImageNotFound {
 			i.logger.Info().Str("image_id", id).Msg("Image not found when getting status")
-			return "", ErrImageNotFound
+			return nil, ErrImageNotFound
 		}
 		i.logger.Error().Err(err).Str("image_id", id).Msg("Failed to get image status from DB")
-		return "", fmt.Errorf("%w: %v", ErrDatabaseError, err)
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
 	}
-	return img.Status, nil
+	return img, nil
+}
+
+func (i *ImageUsecase) GetMetadata(ctx context.Context, id string) (*domain.Image, error) {
+	i.logger.Debug().Str("image_id", id).Msg("Getting image metadata")
+	img, err := i.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == ErrImageNotFound {
+			i.logger.Info().Str("image_id", id).Msg("Image not found when getting metadata")
+			return nil, ErrImageNotFound
+		}
+		i.logger.Error().Err(err).Str("image_id", id).Msg("Failed to get image metadata from DB")
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return img, nil
+}
+
+func (i *ImageUsecase) GetSrcset(ctx context.Context, id string) (*domain.Image, []domain.ProcessedImage, error) {
+	img, err := i.GetMetadata(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	processed, err := i.repo.GetProcessedImages(ctx, id)
+	if err != nil {
+		i.logger.Error().Err(err).Str("image_id", id).Msg("Failed to get processed images from DB")
+		return nil, nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	var responsive []domain.ProcessedImage
+	for _, item := range processed {
+		if item.Operation == domain.OpResponsive {
+			responsive = append(responsive, item)
+		}
+	}
+	if len(responsive) == 0 {
+		i.logger.Info().Str("image_id", id).Msg("Responsive variants not found")
+		return nil, nil, ErrProcessedImageNotFound
+	}
+	return img, responsive, nil
 }
 
 func (i *ImageUsecase) DeleteImage(ctx context.Context, id string) error {
@@ -176,9 +339,18 @@ func (i *ImageUsecase) DeleteImage(ctx context.Context, id string) error {
 	if err := i.fileRepo.DeleteObject(ctx, img.OriginalPath); err != nil {
 		i.logger.Error().Err(err).Str("path", img.OriginalPath).Msg("Failed to delete original file")
 	}
-	processedPrefix := fmt.Sprintf("processed/%s/", id)
-	if err := i.fileRepo.DeleteObjectsWithPrefix(ctx, processedPrefix); err != nil {
-		i.logger.Error().Err(err).Str("prefix", processedPrefix).Msg("Failed to delete processed files")
+	processed, err := i.repo.GetProcessedImages(ctx, id)
+	if err != nil {
+		i.logger.Error().Err(err).Str("image_id", id).Msg("Failed to list processed images for deletion")
+	}
+	for _, variant := range processed {
+		if err := i.fileRepo.DeleteObject(ctx, variant.Path); err != nil {
+			i.logger.Error().Err(err).Str("path", variant.Path).Msg("Failed to delete processed file")
+		}
+	}
+	transformPrefix := fmt.Sprintf("processed/transform/%s/", id)
+	if err := i.fileRepo.DeleteObjectsWithPrefix(ctx, transformPrefix); err != nil {
+		i.logger.Error().Err(err).Str("prefix", transformPrefix).Msg("Failed to delete transformed files")
 	}
 	if err := i.repo.DeleteProcessedImages(ctx, id); err != nil {
 		i.logger.Error().Err(err).Str("image_id", id).Msg("Failed to delete processed images from DB")
@@ -191,25 +363,6 @@ func (i *ImageUsecase) DeleteImage(ctx context.Context, id string) error {
 	return nil
 }
 
-func (i *ImageUsecase) ListImages(ctx context.Context, limit, offset int) ([]domain.Image, error) {
-	return i.repo.List(ctx, limit, offset)
-}
-
-func getFormatFromContentType(contentType string) domain.ImageFormat {
-	switch {
-	case strings.Contains(contentType, "jpeg"):
-		return domain.FormatJPEG
-	case strings.Contains(contentType, "png"):
-		return domain.FormatPNG
-	case strings.Contains(contentType, "gif"):
-		return domain.FormatGIF
-	case strings.Contains(contentType, "webp"):
-		return domain.FormatWebP
-	case strings.Contains(contentType, "bmp"):
-		return domain.FormatBMP
-	case strings.Contains(contentType, "tiff"):
-		return domain.FormatTIFF
-	default:
-		return domain.FormatJPEG
-	}
+func (i *ImageUsecase) ListImages(ctx context.Context, limit, offset int, filter *domain.ColorFilter) ([]domain.Image, error) {
+	return i.repo.List(ctx, limit, offset, filter)
 }
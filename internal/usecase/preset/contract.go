@@ -0,0 +1,15 @@
+package preset
+
+import (
+	"context"
+
+	"image-processor/internal/domain"
+)
+
+type presetRepository interface {
+	Create(ctx context.Context, preset *domain.Preset) error
+	GetByName(ctx context.Context, name string) (*domain.Preset, error)
+	List(ctx context.Context) ([]domain.Preset, error)
+	Update(ctx context.Context, preset *domain.Preset) error
+	Delete(ctx context.Context, name string) error
+}
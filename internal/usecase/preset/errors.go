@@ -0,0 +1,9 @@
+package preset
+
+import "errors"
+
+var (
+	ErrPresetNotFound = errors.New("preset not found")
+	ErrPresetExists   = errors.New("preset already exists")
+	ErrDatabaseError  = errors.New("database error")
+)
@@ -0,0 +1,81 @@
+package preset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"image-processor/internal/domain"
+	preset_repo "image-processor/internal/repository/preset"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type PresetUsecase struct {
+	repo   presetRepository
+	logger *zlog.Zerolog
+}
+
+func NewPresetUsecase(repo presetRepository, logger *zlog.Zerolog) *PresetUsecase {
+	return &PresetUsecase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (u *PresetUsecase) CreatePreset(ctx context.Context, preset *domain.Preset) error {
+	if err := u.repo.Create(ctx, preset); err != nil {
+		if errors.Is(err, preset_repo.ErrPresetExists) {
+			return ErrPresetExists
+		}
+		u.logger.Error().Err(err).Str("preset", preset.Name).Msg("Failed to create preset")
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	u.logger.Info().Str("preset", preset.Name).Int("operations", len(preset.Operations)).Msg("Preset created")
+	return nil
+}
+
+func (u *PresetUsecase) GetPreset(ctx context.Context, name string) (*domain.Preset, error) {
+	preset, err := u.repo.GetByName(ctx, name)
+	if err != nil {
+		u.logger.Error().Err(err).Str("preset", name).Msg("Failed to get preset")
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if preset == nil {
+		return nil, ErrPresetNotFound
+	}
+	return preset, nil
+}
+
+func (u *PresetUsecase) ListPresets(ctx context.Context) ([]domain.Preset, error) {
+	presets, err := u.repo.List(ctx)
+	if err != nil {
+		u.logger.Error().Err(err).Msg("Failed to list presets")
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return presets, nil
+}
+
+func (u *PresetUsecase) UpdatePreset(ctx context.Context, preset *domain.Preset) error {
+	if err := u.repo.Update(ctx, preset); err != nil {
+		if errors.Is(err, preset_repo.ErrPresetNotFound) {
+			return ErrPresetNotFound
+		}
+		u.logger.Error().Err(err).Str("preset", preset.Name).Msg("Failed to update preset")
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	u.logger.Info().Str("preset", preset.Name).Int("version", preset.Version).Msg("Preset updated")
+	return nil
+}
+
+func (u *PresetUsecase) DeletePreset(ctx context.Context, name string) error {
+	if err := u.repo.Delete(ctx, name); err != nil {
+		if errors.Is(err, preset_repo.ErrPresetNotFound) {
+			return ErrPresetNotFound
+		}
+		u.logger.Error().Err(err).Str("preset", name).Msg("Failed to delete preset")
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	u.logger.Info().Str("preset", name).Msg("Preset deleted")
+	return nil
+}
@@ -0,0 +1,113 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/usecase/processor/operations"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+const maxCachedAssets = 32
+
+type assetCache struct {
+	mu     sync.Mutex
+	images map[string]image.Image
+	fonts  map[string]*sfnt.Font
+}
+
+func (p *ImageProcessor) LoadAsset(ctx context.Context, id string) (image.Image, error) {
+	if !domain.IsValidWatermarkID(id) {
+		return nil, fmt.Errorf("invalid watermark id %q", id)
+	}
+	p.assets.mu.Lock()
+	cached, ok := p.assets.images[id]
+	p.assets.mu.Unlock()
+	if ok {
+		if err := p.checkAsset(ctx, domain.WatermarkAssetPath(id)); err != nil {
+			p.assets.mu.Lock()
+			delete(p.assets.images, id)
+			p.assets.mu.Unlock()
+			return nil, fmt.Errorf("failed to get watermark from storage: %w", err)
+		}
+		return cached, nil
+	}
+	reader, err := p.fileRepo.GetObject(ctx, domain.WatermarkAssetPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watermark from storage: %w", err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(io.LimitReader(reader, domain.MaxWatermarkAssetSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watermark: %w", err)
+	}
+	img, _, err := p.decoders.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode watermark: %w", err)
+	}
+	p.assets.mu.Lock()
+	if p.assets.images == nil || len(p.assets.images) >= maxCachedAssets {
+		p.assets.images = make(map[string]image.Image)
+	}
+	p.assets.images[id] = img
+	p.assets.mu.Unlock()
+	return img, nil
+}
+
+func (p *ImageProcessor) LoadFont(ctx context.Context, id string) (*sfnt.Font, error) {
+	if !domain.IsValidFontID(id) {
+		return nil, fmt.Errorf("invalid font id %q", id)
+	}
+	p.assets.mu.Lock()
+	cached, ok := p.assets.fonts[id]
+	p.assets.mu.Unlock()
+	if ok {
+		if err := p.checkAsset(ctx, domain.FontAssetPath(id)); err != nil {
+			p.assets.mu.Lock()
+			delete(p.assets.fonts, id)
+			p.assets.mu.Unlock()
+			return nil, fmt.Errorf("failed to get font from storage: %w", err)
+		}
+		return cached, nil
+	}
+	reader, err := p.fileRepo.GetObject(ctx, domain.FontAssetPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get font from storage: %w", err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(io.LimitReader(reader, domain.MaxFontAssetSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font: %w", err)
+	}
+	parsed, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %w", err)
+	}
+	p.assets.mu.Lock()
+	if p.assets.fonts == nil || len(p.assets.fonts) >= maxCachedAssets {
+		p.assets.fonts = make(map[string]*sfnt.Font)
+	}
+	p.assets.fonts[id] = parsed
+	p.assets.mu.Unlock()
+	return parsed, nil
+}
+
+func (p *ImageProcessor) checkAsset(ctx context.Context, path string) error {
+	exists, err := p.fileRepo.ObjectExists(ctx, path)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("object %s not found", path)
+	}
+	return nil
+}
+
+func (p *ImageProcessor) operationContext(ctx context.Context) context.Context {
+	return operations.WithAssetLoader(operations.WithDefaultKernel(ctx, p.kernel), p)
+}
@@ -8,7 +8,12 @@ import (
 type fileRepository interface {
 	SaveOriginal(ctx context.Context, filename string, data io.Reader, size int64) (string, error)
 	GetObject(ctx context.Context, path string) (io.ReadCloser, error)
+	ObjectExists(ctx context.Context, path string) (bool, error)
 	SaveProcessed(ctx context.Context, path string, data io.Reader, size int64, contentType string) error
 	DeleteObject(ctx context.Context, path string) error
 	DeleteObjectsWithPrefix(ctx context.Context, prefix string) error
 }
+
+type originalRepository interface {
+	UpdateOriginal(ctx context.Context, id, path string, size int64, mimeType string) error
+}
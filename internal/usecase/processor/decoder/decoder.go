@@ -0,0 +1,152 @@
+package decoder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"image-processor/internal/domain"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+	"golang.org/x/image/webp"
+)
+
+var (
+	ErrUnknownFormat    = errors.New("unknown image format")
+	ErrDuplicateDecoder = errors.New("decoder already registered")
+	ErrInvalidDecoder   = errors.New("invalid decoder")
+)
+
+type Format struct {
+	Name       domain.ImageFormat
+	MimeType   string
+	Extensions []string
+	Magic      []string
+	Decode     func(r io.Reader) (image.Image, error)
+}
+
+type Registry struct {
+	mu      sync.RWMutex
+	formats []Format
+}
+
+var defaultRegistry = NewRegistry()
+
+func init() {
+	for _, format := range []Format{
+		{Name: domain.FormatJPEG, MimeType: "image/jpeg", Extensions: []string{".jpg", ".jpeg"}, Magic: []string{"\xff\xd8"}, Decode: jpeg.Decode},
+		{Name: domain.FormatPNG, MimeType: "image/png", Extensions: []string{".png"}, Magic: []string{"\x89PNG\r\n\x1a\n"}, Decode: png.Decode},
+		{Name: domain.FormatGIF, MimeType: "image/gif", Extensions: []string{".gif"}, Magic: []string{"GIF87a", "GIF89a"}, Decode: gif.Decode},
+		{Name: domain.FormatWebP, MimeType: "image/webp", Extensions: []string{".webp"}, Magic: []string{"RIFF????WEBPVP8"}, Decode: webp.Decode},
+		{Name: domain.FormatBMP, MimeType: "image/bmp", Extensions: []string{".bmp"}, Magic: []string{"BM"}, Decode: bmp.Decode},
+		{Name: domain.FormatTIFF, MimeType: "image/tiff", Extensions: []string{".tif", ".tiff"}, Magic: []string{"II*\x00", "MM\x00*"}, Decode: tiff.Decode},
+	} {
+		MustRegister(format)
+	}
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func Default() *Registry {
+	return defaultRegistry
+}
+
+func Register(format Format) error {
+	return defaultRegistry.Register(format)
+}
+
+func MustRegister(format Format) {
+	if err := Register(format); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Registry) Register(format Format) error {
+	if format.Name == "" || format.Decode == nil || len(format.Magic) == 0 {
+		return fmt.Errorf("%w: format must have a name, magic and decode function", ErrInvalidDecoder)
+	}
+	format.Name = domain.NormalizeFormat(string(format.Name))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.formats {
+		if existing.Name == format.Name {
+			return fmt.Errorf("%w: %s", ErrDuplicateDecoder, format.Name)
+		}
+	}
+	r.formats = append(r.formats, format)
+	return nil
+}
+
+func (r *Registry) Sniff(header []byte) (Format, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, format := range r.formats {
+		for _, magic := range format.Magic {
+			if matchMagic(magic, header) {
+				return format, true
+			}
+		}
+	}
+	return Format{}, false
+}
+
+func (r *Registry) DetectFormat(header []byte) (domain.ImageFormat, string, bool) {
+	format, ok := r.Sniff(header)
+	return format.Name, format.MimeType, ok
+}
+
+func (r *Registry) Decode(data []byte) (image.Image, domain.ImageFormat, error) {
+	format, ok := r.Sniff(data)
+	if !ok {
+		return nil, "", ErrUnknownFormat
+	}
+	img, err := format.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, format.Name, err
+	}
+	return img, format.Name, nil
+}
+
+func (r *Registry) Extensions() []string {
+	r.mu.RLock()
+	var extensions []string
+	for _, format := range r.formats {
+		extensions = append(extensions, format.Extensions...)
+	}
+	r.mu.RUnlock()
+	sort.Strings(extensions)
+	return extensions
+}
+
+func (r *Registry) IsAllowedExtension(ext string) bool {
+	ext = strings.ToLower(ext)
+	for _, allowed := range r.Extensions() {
+		if allowed == ext {
+			return true
+		}
+	}
+	return false
+}
+
+func matchMagic(magic string, header []byte) bool {
+	if len(magic) > len(header) {
+		return false
+	}
+	for i := 0; i < len(magic); i++ {
+		if magic[i] != '?' && magic[i] != header[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package encoder
+
+import (
+	"image"
+	"io"
+
+	"image-processor/internal/domain"
+
+	"golang.org/x/image/bmp"
+)
+
+type BMPEncoder struct{}
+
+func NewBMPEncoder() *BMPEncoder {
+	return &BMPEncoder{}
+}
+
+func (e *BMPEncoder) Format() domain.ImageFormat {
+	return domain.FormatBMP
+}
+
+func (e *BMPEncoder) MimeType() string {
+	return "image/bmp"
+}
+
+func (e *BMPEncoder) Encode(w io.Writer, img image.Image, opts domain.OutputOptions) error {
+	return bmp.Encode(w, img)
+}
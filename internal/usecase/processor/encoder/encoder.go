@@ -0,0 +1,151 @@
+package encoder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"io"
+	"sort"
+	"sync"
+
+	"image-processor/internal/domain"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported output format")
+	ErrDuplicateEncoder  = errors.New("encoder already registered")
+	ErrNoFrames          = errors.New("no frames to encode")
+)
+
+type Encoder interface {
+	Format() domain.ImageFormat
+	MimeType() string
+	Encode(w io.Writer, img image.Image, opts domain.OutputOptions) error
+}
+
+type AnimationEncoder interface {
+	Encoder
+	EncodeAnimation(w io.Writer, frames []image.Image, source *gif.GIF, opts domain.OutputOptions) error
+}
+
+type Result struct {
+	Data     []byte
+	Format   domain.ImageFormat
+	MimeType string
+}
+
+type Registry struct {
+	mu       sync.RWMutex
+	encoders map[domain.ImageFormat]Encoder
+}
+
+var defaultRegistry = NewRegistry()
+
+func init() {
+	for _, enc := range []Encoder{
+		NewJPEGEncoder(),
+		NewPNGEncoder(),
+		NewGIFEncoder(),
+		NewBMPEncoder(),
+		NewTIFFEncoder(),
+	} {
+		MustRegister(enc)
+	}
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		encoders: make(map[domain.ImageFormat]Encoder),
+	}
+}
+
+func Default() *Registry {
+	return defaultRegistry
+}
+
+func Register(enc Encoder) error {
+	return defaultRegistry.Register(enc)
+}
+
+func MustRegister(enc Encoder) {
+	if err := Register(enc); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Registry) Register(enc Encoder) error {
+	format := domain.NormalizeFormat(string(enc.Format()))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.encoders[format]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateEncoder, format)
+	}
+	r.encoders[format] = enc
+	return nil
+}
+
+func (r *Registry) Lookup(format domain.ImageFormat) (Encoder, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	enc, ok := r.encoders[domain.NormalizeFormat(string(format))]
+	return enc, ok
+}
+
+func (r *Registry) CanEncode(format domain.ImageFormat) bool {
+	_, ok := r.Lookup(format)
+	return ok
+}
+
+func (r *Registry) Formats() []domain.ImageFormat {
+	r.mu.RLock()
+	formats := make([]domain.ImageFormat, 0, len(r.encoders))
+	for format := range r.encoders {
+		formats = append(formats, format)
+	}
+	r.mu.RUnlock()
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i] < formats[j]
+	})
+	return formats
+}
+
+func (r *Registry) EncodeAnimation(frames []image.Image, source *gif.GIF, opts domain.OutputOptions) (*Result, error) {
+	if len(frames) == 0 {
+		return nil, ErrNoFrames
+	}
+	enc, ok := r.Lookup(opts.Format)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, opts.Format)
+	}
+	animated, ok := enc.(AnimationEncoder)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s does not support animation", ErrUnsupportedFormat, enc.Format())
+	}
+	buf := new(bytes.Buffer)
+	if err := animated.EncodeAnimation(buf, frames, source, opts); err != nil {
+		return nil, fmt.Errorf("failed to encode animated %s: %w", enc.Format(), err)
+	}
+	return &Result{
+		Data:     buf.Bytes(),
+		Format:   enc.Format(),
+		MimeType: enc.MimeType(),
+	}, nil
+}
+
+func (r *Registry) Encode(img image.Image, opts domain.OutputOptions) (*Result, error) {
+	enc, ok := r.Lookup(opts.Format)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, opts.Format)
+	}
+	buf := new(bytes.Buffer)
+	if err := enc.Encode(buf, img, opts); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", enc.Format(), err)
+	}
+	return &Result{
+		Data:     buf.Bytes(),
+		Format:   enc.Format(),
+		MimeType: enc.MimeType(),
+	}, nil
+}
@@ -0,0 +1,111 @@
+package encoder
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+
+	"image-processor/internal/domain"
+)
+
+type GIFEncoder struct{}
+
+func NewGIFEncoder() *GIFEncoder {
+	return &GIFEncoder{}
+}
+
+func (e *GIFEncoder) Format() domain.ImageFormat {
+	return domain.FormatGIF
+}
+
+func (e *GIFEncoder) MimeType() string {
+	return "image/gif"
+}
+
+func (e *GIFEncoder) Encode(w io.Writer, img image.Image, opts domain.OutputOptions) error {
+	return gif.Encode(w, Paletted(img, opts), nil)
+}
+
+func (e *GIFEncoder) EncodeAnimation(w io.Writer, frames []image.Image, source *gif.GIF, opts domain.OutputOptions) error {
+	bounds := frames[0].Bounds()
+	out := &gif.GIF{
+		LoopCount: source.LoopCount,
+		Config:    image.Config{Width: bounds.Dx(), Height: bounds.Dy()},
+	}
+	disposal := byte(gif.DisposalNone)
+	for _, frame := range frames {
+		if !isOpaque(frame) {
+			disposal = gif.DisposalBackground
+			break
+		}
+	}
+	for idx, frame := range frames {
+		out.Image = append(out.Image, Paletted(frame, opts))
+		delay := 0
+		if idx < len(source.Delay) {
+			delay = source.Delay[idx]
+		}
+		out.Delay = append(out.Delay, delay)
+		out.Disposal = append(out.Disposal, disposal)
+	}
+	return gif.EncodeAll(w, out)
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func Paletted(img image.Image, opts domain.OutputOptions) *image.Paletted {
+	numColors := opts.GIFColors
+	if numColors == 0 {
+		numColors = 256
+	}
+	if p, ok := img.(*image.Paletted); ok && len(p.Palette) <= numColors && p.Rect.Min == (image.Point{}) {
+		return p
+	}
+	var pal color.Palette
+	switch opts.GIFPalette {
+	case domain.GIFPalettePlan9:
+		pal = limitPalette(palette.Plan9, numColors)
+	case domain.GIFPaletteWebSafe:
+		pal = limitPalette(palette.WebSafe, numColors)
+	default:
+		pal = MedianCut(img, numColors)
+	}
+	return palettize(img, pal, ditherer(opts))
+}
+
+func palettize(img image.Image, pal color.Palette, drawer draw.Drawer) *image.Paletted {
+	bounds := img.Bounds()
+	dst := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), pal)
+	drawer.Draw(dst, dst.Bounds(), img, bounds.Min)
+	return dst
+}
+
+func ditherer(opts domain.OutputOptions) draw.Drawer {
+	if opts.GIFDither != nil && !*opts.GIFDither {
+		return draw.Src
+	}
+	return draw.FloydSteinberg
+}
+
+func limitPalette(pal color.Palette, numColors int) color.Palette {
+	if len(pal) > numColors {
+		return pal[:numColors]
+	}
+	return pal
+}
@@ -0,0 +1,31 @@
+package encoder
+
+import (
+	"image"
+	"image/jpeg"
+	"io"
+
+	"image-processor/internal/domain"
+)
+
+type JPEGEncoder struct{}
+
+func NewJPEGEncoder() *JPEGEncoder {
+	return &JPEGEncoder{}
+}
+
+func (e *JPEGEncoder) Format() domain.ImageFormat {
+	return domain.FormatJPEG
+}
+
+func (e *JPEGEncoder) MimeType() string {
+	return "image/jpeg"
+}
+
+func (e *JPEGEncoder) Encode(w io.Writer, img image.Image, opts domain.OutputOptions) error {
+	quality := opts.Quality
+	if quality == 0 {
+		quality = domain.DefaultJPEGQuality
+	}
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+}
@@ -0,0 +1,41 @@
+package encoder
+
+import (
+	"image"
+	"image/png"
+	"io"
+
+	"image-processor/internal/domain"
+)
+
+type PNGEncoder struct{}
+
+func NewPNGEncoder() *PNGEncoder {
+	return &PNGEncoder{}
+}
+
+func (e *PNGEncoder) Format() domain.ImageFormat {
+	return domain.FormatPNG
+}
+
+func (e *PNGEncoder) MimeType() string {
+	return "image/png"
+}
+
+func (e *PNGEncoder) Encode(w io.Writer, img image.Image, opts domain.OutputOptions) error {
+	encoder := &png.Encoder{CompressionLevel: compressionLevel(opts.PNGCompression)}
+	return encoder.Encode(w, img)
+}
+
+func compressionLevel(compression domain.PNGCompression) png.CompressionLevel {
+	switch compression {
+	case domain.PNGCompressionNone:
+		return png.NoCompression
+	case domain.PNGCompressionSpeed:
+		return png.BestSpeed
+	case domain.PNGCompressionBest:
+		return png.BestCompression
+	default:
+		return png.DefaultCompression
+	}
+}
@@ -0,0 +1,122 @@
+package encoder
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+const maxQuantizeSamples = 1 << 16
+
+type colorBox struct {
+	pixels []color.NRGBA
+}
+
+func MedianCut(img image.Image, numColors int) color.Palette {
+	pixels, transparent := samplePixels(img)
+	if transparent {
+		numColors--
+	}
+	pal := make(color.Palette, 0, numColors+1)
+	if transparent {
+		pal = append(pal, color.NRGBA{})
+	}
+	if len(pixels) == 0 || numColors < 1 {
+		return append(pal, color.NRGBA{A: 255})
+	}
+	boxes := []colorBox{{pixels: pixels}}
+	for len(boxes) < numColors {
+		idx, channel, spread := -1, 0, 0
+		for i, box := range boxes {
+			if len(box.pixels) < 2 {
+				continue
+			}
+			c, s := box.widestChannel()
+			if s > spread {
+				idx, channel, spread = i, c, s
+			}
+		}
+		if idx < 0 {
+			break
+		}
+		left, right := boxes[idx].split(channel)
+		boxes[idx] = left
+		boxes = append(boxes, right)
+	}
+	for _, box := range boxes {
+		pal = append(pal, box.average())
+	}
+	return pal
+}
+
+func samplePixels(img image.Image) ([]color.NRGBA, bool) {
+	bounds := img.Bounds()
+	step := 1
+	for (bounds.Dx()/step)*(bounds.Dy()/step) > maxQuantizeSamples {
+		step++
+	}
+	pixels := make([]color.NRGBA, 0, (bounds.Dx()/step+1)*(bounds.Dy()/step+1))
+	transparent := false
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				transparent = true
+				continue
+			}
+			c.A = 255
+			pixels = append(pixels, c)
+		}
+	}
+	return pixels, transparent
+}
+
+func channel(c color.NRGBA, ch int) uint8 {
+	switch ch {
+	case 0:
+		return c.R
+	case 1:
+		return c.G
+	default:
+		return c.B
+	}
+}
+
+func (b colorBox) widestChannel() (int, int) {
+	best, spread := 0, -1
+	for ch := 0; ch < 3; ch++ {
+		lo, hi := uint8(255), uint8(0)
+		for _, p := range b.pixels {
+			v := channel(p, ch)
+			if v < lo {
+				lo = v
+			}
+			if v > hi {
+				hi = v
+			}
+		}
+		if int(hi)-int(lo) > spread {
+			best, spread = ch, int(hi)-int(lo)
+		}
+	}
+	return best, spread
+}
+
+func (b colorBox) split(ch int) (colorBox, colorBox) {
+	sort.Slice(b.pixels, func(i, j int) bool {
+		return channel(b.pixels[i], ch) < channel(b.pixels[j], ch)
+	})
+	mid := len(b.pixels) / 2
+	return colorBox{pixels: b.pixels[:mid]}, colorBox{pixels: b.pixels[mid:]}
+}
+
+func (b colorBox) average() color.NRGBA {
+	var r, g, bl int
+	for _, p := range b.pixels {
+		r += int(p.R)
+		g += int(p.G)
+		bl += int(p.B)
+	}
+	n := len(b.pixels)
+	return color.NRGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: 255}
+}
@@ -0,0 +1,32 @@
+package encoder
+
+import (
+	"image"
+	"io"
+
+	"image-processor/internal/domain"
+
+	"golang.org/x/image/tiff"
+)
+
+type TIFFEncoder struct{}
+
+func NewTIFFEncoder() *TIFFEncoder {
+	return &TIFFEncoder{}
+}
+
+func (e *TIFFEncoder) Format() domain.ImageFormat {
+	return domain.FormatTIFF
+}
+
+func (e *TIFFEncoder) MimeType() string {
+	return "image/tiff"
+}
+
+func (e *TIFFEncoder) Encode(w io.Writer, img image.Image, opts domain.OutputOptions) error {
+	options := &tiff.Options{Compression: tiff.Deflate}
+	if opts.TIFFCompression == domain.TIFFCompressionNone {
+		options.Compression = tiff.Uncompressed
+	}
+	return tiff.Encode(w, img, options)
+}
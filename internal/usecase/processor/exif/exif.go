@@ -0,0 +1,347 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoExif               = errors.New("no exif data")
+	ErrInvalidExif          = errors.New("invalid exif data")
+	ErrUnsupportedContainer = errors.New("unsupported image container")
+	ErrMalformedContainer   = errors.New("malformed image container")
+	ErrPayloadTooLarge      = errors.New("exif payload does not fit into a jpeg app1 segment")
+)
+
+type IFD int
+
+const (
+	IFD0 IFD = iota
+	ExifIFD
+	GPSIFD
+)
+
+type Tag uint16
+
+const (
+	TagMake             Tag = 0x010f
+	TagModel            Tag = 0x0110
+	TagOrientation      Tag = 0x0112
+	TagSoftware         Tag = 0x0131
+	TagDateTime         Tag = 0x0132
+	TagExifIFD          Tag = 0x8769
+	TagGPSIFD           Tag = 0x8825
+	TagDateTimeOriginal Tag = 0x9003
+	TagLensModel        Tag = 0xa434
+
+	TagGPSLatitudeRef  Tag = 0x0001
+	TagGPSLatitude     Tag = 0x0002
+	TagGPSLongitudeRef Tag = 0x0003
+	TagGPSLongitude    Tag = 0x0004
+	TagGPSAltitudeRef  Tag = 0x0005
+	TagGPSAltitude     Tag = 0x0006
+)
+
+const dateTimeLayout = "2006:01:02 15:04:05"
+
+const (
+	typeByte      = 1
+	typeASCII     = 2
+	typeShort     = 3
+	typeLong      = 4
+	typeRational  = 5
+	typeUndefined = 7
+	typeSLong     = 9
+	typeSRational = 10
+)
+
+const maxIFDEntries = 1000
+
+type entry struct {
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+type Data struct {
+	order binary.ByteOrder
+	ifds  map[IFD]map[Tag]entry
+}
+
+func Parse(payload []byte) (*Data, error) {
+	if len(payload) < 8 {
+		return nil, ErrInvalidExif
+	}
+	var order binary.ByteOrder
+	switch string(payload[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("%w: bad byte order", ErrInvalidExif)
+	}
+	if order.Uint16(payload[2:4]) != 42 {
+		return nil, fmt.Errorf("%w: bad magic", ErrInvalidExif)
+	}
+	d := &Data{order: order, ifds: make(map[IFD]map[Tag]entry)}
+	ifd0, err := d.readIFD(payload, order.Uint32(payload[4:8]))
+	if err != nil {
+		return nil, err
+	}
+	d.ifds[IFD0] = ifd0
+	for tag, ifd := range map[Tag]IFD{TagExifIFD: ExifIFD, TagGPSIFD: GPSIFD} {
+		offset, ok := d.Uint(IFD0, tag)
+		if !ok {
+			continue
+		}
+		if entries, err := d.readIFD(payload, offset); err == nil {
+			d.ifds[ifd] = entries
+		}
+	}
+	return d, nil
+}
+
+func (d *Data) readIFD(payload []byte, offset uint32) (map[Tag]entry, error) {
+	if int64(offset)+2 > int64(len(payload)) {
+		return nil, fmt.Errorf("%w: ifd offset out of range", ErrInvalidExif)
+	}
+	count := int(d.order.Uint16(payload[offset:]))
+	if count > maxIFDEntries || int64(offset)+2+int64(count)*12 > int64(len(payload)) {
+		return nil, fmt.Errorf("%w: ifd entries out of range", ErrInvalidExif)
+	}
+	entries := make(map[Tag]entry, count)
+	for i := 0; i < count; i++ {
+		raw := payload[int(offset)+2+i*12:]
+		e := entry{typ: d.order.Uint16(raw[2:4]), count: d.order.Uint32(raw[4:8])}
+		size := int64(typeSize(e.typ)) * int64(e.count)
+		if size == 0 {
+			continue
+		}
+		if size <= 4 {
+			e.data = raw[8 : 8+size]
+		} else {
+			valueOffset := int64(d.order.Uint32(raw[8:12]))
+			if valueOffset+size > int64(len(payload)) {
+				continue
+			}
+			e.data = payload[valueOffset : valueOffset+size]
+		}
+		entries[Tag(d.order.Uint16(raw[0:2]))] = e
+	}
+	return entries, nil
+}
+
+func (d *Data) Uint(ifd IFD, tag Tag) (uint32, bool) {
+	e, ok := d.ifds[ifd][tag]
+	if !ok {
+		return 0, false
+	}
+	switch e.typ {
+	case typeByte, typeUndefined:
+		return uint32(e.data[0]), true
+	case typeShort:
+		return uint32(d.order.Uint16(e.data)), true
+	case typeLong, typeSLong:
+		return d.order.Uint32(e.data), true
+	default:
+		return 0, false
+	}
+}
+
+func (d *Data) String(ifd IFD, tag Tag) (string, bool) {
+	e, ok := d.ifds[ifd][tag]
+	if !ok || e.typ != typeASCII {
+		return "", false
+	}
+	value := strings.TrimSpace(strings.TrimRight(string(e.data), "\x00"))
+	return value, value != ""
+}
+
+func (d *Data) Rationals(ifd IFD, tag Tag) ([]float64, bool) {
+	e, ok := d.ifds[ifd][tag]
+	if !ok || (e.typ != typeRational && e.typ != typeSRational) {
+		return nil, false
+	}
+	values := make([]float64, e.count)
+	for i := range values {
+		num, den := d.order.Uint32(e.data[i*8:]), d.order.Uint32(e.data[i*8+4:])
+		if den == 0 {
+			return nil, false
+		}
+		if e.typ == typeSRational {
+			values[i] = float64(int32(num)) / float64(int32(den))
+		} else {
+			values[i] = float64(num) / float64(den)
+		}
+	}
+	return values, true
+}
+
+func (d *Data) Camera() (string, string) {
+	cameraMake, _ := d.String(IFD0, TagMake)
+	model, _ := d.String(IFD0, TagModel)
+	return cameraMake, model
+}
+
+func (d *Data) TakenAt() (time.Time, bool) {
+	value, ok := d.String(ExifIFD, TagDateTimeOriginal)
+	if !ok {
+		value, ok = d.String(IFD0, TagDateTime)
+	}
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(dateTimeLayout, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func (d *Data) GPS() (float64, float64, bool) {
+	lat, ok := d.coordinate(TagGPSLatitude, TagGPSLatitudeRef, "S")
+	if !ok {
+		return 0, 0, false
+	}
+	lon, ok := d.coordinate(TagGPSLongitude, TagGPSLongitudeRef, "W")
+	if !ok {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+func (d *Data) Altitude() (float64, bool) {
+	values, ok := d.Rationals(GPSIFD, TagGPSAltitude)
+	if !ok || len(values) == 0 {
+		return 0, false
+	}
+	if ref, ok := d.Uint(GPSIFD, TagGPSAltitudeRef); ok && ref == 1 {
+		return -values[0], true
+	}
+	return values[0], true
+}
+
+func (d *Data) coordinate(tag, refTag Tag, negative string) (float64, bool) {
+	values, ok := d.Rationals(GPSIFD, tag)
+	if !ok || len(values) != 3 {
+		return 0, false
+	}
+	value := values[0] + values[1]/60 + values[2]/3600
+	if ref, _ := d.String(GPSIFD, refTag); ref == negative {
+		value = -value
+	}
+	return value, true
+}
+
+func (d *Data) Orientation() int {
+	value, ok := d.Uint(IFD0, TagOrientation)
+	if !ok || value < 1 || value > 8 {
+		return 0
+	}
+	return int(value)
+}
+
+func Extract(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xff\xd8")):
+		return extractJPEG(data)
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return extractPNG(data)
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return extractWebP(data)
+	case bytes.HasPrefix(data, []byte("II*\x00")), bytes.HasPrefix(data, []byte("MM\x00*")):
+		return data, nil
+	default:
+		return nil, ErrNoExif
+	}
+}
+
+func Read(data []byte) (*Data, error) {
+	payload, err := Extract(data)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(payload)
+}
+
+func extractJPEG(data []byte) ([]byte, error) {
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xff {
+			return nil, ErrNoExif
+		}
+		marker := data[pos+1]
+		if marker == 0xd8 || (marker >= 0xd0 && marker <= 0xd7) || marker == 0xff {
+			pos++
+			continue
+		}
+		if marker == 0xda || marker == 0xd9 {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return nil, ErrNoExif
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:], nil
+		}
+		pos += 2 + length
+	}
+	return nil, ErrNoExif
+}
+
+func extractPNG(data []byte) ([]byte, error) {
+	pos := 8
+	for pos+8 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		chunkType := string(data[pos+4 : pos+8])
+		if length < 0 || pos+12+length > len(data) {
+			return nil, ErrNoExif
+		}
+		if chunkType == "eXIf" {
+			return data[pos+8 : pos+8+length], nil
+		}
+		if chunkType == "IDAT" || chunkType == "IEND" {
+			break
+		}
+		pos += 12 + length
+	}
+	return nil, ErrNoExif
+}
+
+func extractWebP(data []byte) ([]byte, error) {
+	pos := 12
+	for pos+8 <= len(data) {
+		chunkType := string(data[pos : pos+4])
+		length := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		if length < 0 || pos+8+length > len(data) {
+			return nil, ErrNoExif
+		}
+		if chunkType == "EXIF" {
+			return bytes.TrimPrefix(data[pos+8:pos+8+length], []byte("Exif\x00\x00")), nil
+		}
+		pos += 8 + length + length%2
+	}
+	return nil, ErrNoExif
+}
+
+func typeSize(typ uint16) int {
+	switch typ {
+	case typeByte, typeASCII, typeUndefined:
+		return 1
+	case typeShort:
+		return 2
+	case typeLong, typeSLong:
+		return 4
+	case typeRational, typeSRational:
+		return 8
+	default:
+		return 0
+	}
+}
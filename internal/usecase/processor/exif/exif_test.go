@@ -0,0 +1,113 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"image-processor/internal/domain"
+)
+
+var order = binary.LittleEndian
+
+func asciiEntry(value string) entry {
+	data := append([]byte(value), 0)
+	return entry{typ: typeASCII, count: uint32(len(data)), data: data}
+}
+
+func shortEntry(value uint16) entry {
+	data := make([]byte, 2)
+	order.PutUint16(data, value)
+	return entry{typ: typeShort, count: 1, data: data}
+}
+
+func rationalEntry(values ...[2]uint32) entry {
+	data := make([]byte, 8*len(values))
+	for i, value := range values {
+		order.PutUint32(data[i*8:], value[0])
+		order.PutUint32(data[i*8+4:], value[1])
+	}
+	return entry{typ: typeRational, count: uint32(len(values)), data: data}
+}
+
+func samplePayload() []byte {
+	d := &Data{order: order, ifds: map[IFD]map[Tag]entry{
+		IFD0: {
+			TagMake:        asciiEntry("Canon"),
+			TagModel:       asciiEntry("EOS R5"),
+			TagOrientation: shortEntry(6),
+			tagCopyright:   asciiEntry("Jane Doe"),
+		},
+		ExifIFD: {
+			TagDateTimeOriginal: asciiEntry("2024:05:17 10:30:00"),
+		},
+		GPSIFD: {
+			TagGPSLatitudeRef:  asciiEntry("N"),
+			TagGPSLatitude:     rationalEntry([2]uint32{55, 1}, [2]uint32{45, 1}, [2]uint32{0, 1}),
+			TagGPSLongitudeRef: asciiEntry("W"),
+			TagGPSLongitude:    rationalEntry([2]uint32{37, 1}, [2]uint32{30, 1}, [2]uint32{36, 1}),
+			TagGPSAltitude:     rationalEntry([2]uint32{1505, 10}),
+		},
+	}}
+	return d.Encode()
+}
+
+func TestParse(t *testing.T) {
+	d, err := Parse(samplePayload())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if cameraMake, model := d.Camera(); cameraMake != "Canon" || model != "EOS R5" {
+		t.Errorf("Camera() = %q, %q", cameraMake, model)
+	}
+	if got := d.Orientation(); got != 6 {
+		t.Errorf("Orientation() = %d, want 6", got)
+	}
+	if taken, ok := d.TakenAt(); !ok || !taken.Equal(time.Date(2024, 5, 17, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("TakenAt() = %v, %v", taken, ok)
+	}
+	lat, lon, ok := d.GPS()
+	if !ok || math.Abs(lat-55.75) > 1e-9 || math.Abs(lon+37.51) > 1e-9 {
+		t.Errorf("GPS() = %v, %v, %v; want 55.75, -37.51", lat, lon, ok)
+	}
+	if alt, ok := d.Altitude(); !ok || alt != 150.5 {
+		t.Errorf("Altitude() = %v, %v; want 150.5", alt, ok)
+	}
+	want := []string{domain.MetadataCamera, domain.MetadataCopyright, domain.MetadataDateTime, domain.MetadataGPS, domain.MetadataOrientation}
+	if got := d.Groups(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Groups() = %v, want %v", got, want)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+	}{
+		{"empty", nil},
+		{"too short", []byte("II*\x00")},
+		{"bad byte order", []byte("XX*\x00\x08\x00\x00\x00")},
+		{"bad magic", []byte("II\x2b\x00\x08\x00\x00\x00")},
+		{"ifd offset out of range", []byte("II*\x00\xff\x00\x00\x00")},
+		{"ifd entries out of range", []byte("II*\x00\x08\x00\x00\x00\x05\x00")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.payload); !errors.Is(err, ErrInvalidExif) {
+				t.Errorf("Parse() error = %v, want %v", err, ErrInvalidExif)
+			}
+		})
+	}
+}
+
+func TestExtractTIFF(t *testing.T) {
+	payload := samplePayload()
+	got, err := Extract(payload)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Errorf("Extract() on TIFF = %d bytes, %v; want the whole file", len(got), err)
+	}
+}
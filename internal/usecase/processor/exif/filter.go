@@ -0,0 +1,340 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"sort"
+
+	"image-processor/internal/domain"
+)
+
+const maxAPP1Payload = 0xffff - 2 - 6
+
+const (
+	tagArtist            Tag = 0x013b
+	tagImageDescription  Tag = 0x010e
+	tagCopyright         Tag = 0x8298
+	tagDateTimeDigitized Tag = 0x9004
+	tagOffsetTime        Tag = 0x9010
+	tagOffsetTimeDigit   Tag = 0x9012
+	tagUserComment       Tag = 0x9286
+	tagSubSecTime        Tag = 0x9290
+	tagSubSecTimeDigit   Tag = 0x9292
+	tagInteropIFD        Tag = 0xa005
+	tagBodySerialNumber  Tag = 0xa431
+	tagLensMake          Tag = 0xa433
+	tagLensSerialNumber  Tag = 0xa435
+)
+
+type taggedEntry struct {
+	tag Tag
+	entry
+}
+
+func groupOf(ifd IFD, tag Tag) string {
+	if ifd == GPSIFD {
+		return domain.MetadataGPS
+	}
+	switch tag {
+	case TagOrientation:
+		return domain.MetadataOrientation
+	case TagMake, TagModel, TagLensModel, tagLensMake, tagBodySerialNumber, tagLensSerialNumber:
+		return domain.MetadataCamera
+	case TagDateTime, TagDateTimeOriginal, tagDateTimeDigitized:
+		return domain.MetadataDateTime
+	case tagCopyright:
+		return domain.MetadataCopyright
+	case tagArtist:
+		return domain.MetadataArtist
+	case tagImageDescription, tagUserComment:
+		return domain.MetadataDescription
+	case TagSoftware:
+		return domain.MetadataSoftware
+	}
+	if tag >= tagOffsetTime && tag <= tagOffsetTimeDigit || tag >= tagSubSecTime && tag <= tagSubSecTimeDigit {
+		return domain.MetadataDateTime
+	}
+	return domain.MetadataOther
+}
+
+func isPointer(tag Tag) bool {
+	return tag == TagExifIFD || tag == TagGPSIFD || tag == tagInteropIFD
+}
+
+func (d *Data) Groups() []string {
+	seen := make(map[string]bool)
+	for ifd, entries := range d.ifds {
+		for tag := range entries {
+			if !isPointer(tag) {
+				seen[groupOf(ifd, tag)] = true
+			}
+		}
+	}
+	groups := make([]string, 0, len(seen))
+	for group := range seen {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+func (d *Data) Filter(keep func(group string) bool) *Data {
+	filtered := &Data{order: d.order, ifds: make(map[IFD]map[Tag]entry)}
+	for ifd, entries := range d.ifds {
+		kept := make(map[Tag]entry)
+		for tag, e := range entries {
+			if !isPointer(tag) && keep(groupOf(ifd, tag)) {
+				kept[tag] = e
+			}
+		}
+		if len(kept) > 0 {
+			filtered.ifds[ifd] = kept
+		}
+	}
+	return filtered
+}
+
+func (d *Data) Encode() []byte {
+	ifd0 := d.sorted(IFD0)
+	exifIFD := d.sorted(ExifIFD)
+	gpsIFD := d.sorted(GPSIFD)
+	if len(ifd0)+len(exifIFD)+len(gpsIFD) == 0 {
+		return nil
+	}
+	exifPtr := &taggedEntry{tag: TagExifIFD, entry: entry{typ: typeLong, count: 1, data: make([]byte, 4)}}
+	gpsPtr := &taggedEntry{tag: TagGPSIFD, entry: entry{typ: typeLong, count: 1, data: make([]byte, 4)}}
+	if len(exifIFD) > 0 {
+		ifd0 = append(ifd0, exifPtr)
+	}
+	if len(gpsIFD) > 0 {
+		ifd0 = append(ifd0, gpsPtr)
+	}
+	sort.Slice(ifd0, func(i, j int) bool { return ifd0[i].tag < ifd0[j].tag })
+	exifOffset := 8 + ifdSize(ifd0)
+	gpsOffset := exifOffset
+	if len(exifIFD) > 0 {
+		gpsOffset += ifdSize(exifIFD)
+	}
+	d.order.PutUint32(exifPtr.data, uint32(exifOffset))
+	d.order.PutUint32(gpsPtr.data, uint32(gpsOffset))
+	buf := new(bytes.Buffer)
+	if d.order == binary.LittleEndian {
+		buf.WriteString("II")
+	} else {
+		buf.WriteString("MM")
+	}
+	header := make([]byte, 6)
+	d.order.PutUint16(header, 42)
+	d.order.PutUint32(header[2:], 8)
+	buf.Write(header)
+	d.writeIFD(buf, ifd0, 8)
+	if len(exifIFD) > 0 {
+		d.writeIFD(buf, exifIFD, exifOffset)
+	}
+	if len(gpsIFD) > 0 {
+		d.writeIFD(buf, gpsIFD, gpsOffset)
+	}
+	return buf.Bytes()
+}
+
+func (d *Data) sorted(ifd IFD) []*taggedEntry {
+	entries := make([]*taggedEntry, 0, len(d.ifds[ifd]))
+	for tag, e := range d.ifds[ifd] {
+		if !isPointer(tag) {
+			entries = append(entries, &taggedEntry{tag: tag, entry: e})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].tag < entries[j].tag })
+	return entries
+}
+
+func ifdSize(entries []*taggedEntry) int {
+	size := 2 + 12*len(entries) + 4
+	for _, e := range entries {
+		if len(e.data) > 4 {
+			size += len(e.data) + len(e.data)%2
+		}
+	}
+	return size
+}
+
+func (d *Data) writeIFD(buf *bytes.Buffer, entries []*taggedEntry, start int) {
+	dataOffset := start + 2 + 12*len(entries) + 4
+	raw := make([]byte, 12)
+	d.order.PutUint16(raw, uint16(len(entries)))
+	buf.Write(raw[:2])
+	var values [][]byte
+	for _, e := range entries {
+		d.order.PutUint16(raw[0:], uint16(e.tag))
+		d.order.PutUint16(raw[2:], e.typ)
+		d.order.PutUint32(raw[4:], e.count)
+		copy(raw[8:], []byte{0, 0, 0, 0})
+		if len(e.data) <= 4 {
+			copy(raw[8:], e.data)
+		} else {
+			d.order.PutUint32(raw[8:], uint32(dataOffset))
+			dataOffset += len(e.data) + len(e.data)%2
+			values = append(values, e.data)
+		}
+		buf.Write(raw)
+	}
+	buf.Write([]byte{0, 0, 0, 0})
+	for _, value := range values {
+		buf.Write(value)
+		if len(value)%2 == 1 {
+			buf.WriteByte(0)
+		}
+	}
+}
+
+func Embed(data []byte, format domain.ImageFormat, payload []byte) ([]byte, bool) {
+	if len(payload) == 0 {
+		return data, false
+	}
+	switch format {
+	case domain.FormatJPEG:
+		if len(payload) > maxAPP1Payload || len(data) < 2 || !bytes.HasPrefix(data, []byte("\xff\xd8")) {
+			return data, false
+		}
+		out := make([]byte, 0, len(data)+len(payload)+10)
+		out = append(out, data[:2]...)
+		out = append(out, app1Segment(payload)...)
+		return append(out, data[2:]...), true
+	case domain.FormatPNG:
+		const ihdrEnd = 8 + 8 + 13 + 4
+		if len(data) < ihdrEnd || !bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")) {
+			return data, false
+		}
+		out := make([]byte, 0, len(data)+len(payload)+12)
+		out = append(out, data[:ihdrEnd]...)
+		out = append(out, pngChunk("eXIf", payload)...)
+		return append(out, data[ihdrEnd:]...), true
+	default:
+		return data, false
+	}
+}
+
+func Strip(data []byte, payload []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xff\xd8")):
+		return stripJPEG(data, payload)
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return stripPNG(data, payload)
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return stripWebP(data, payload)
+	default:
+		return nil, ErrUnsupportedContainer
+	}
+}
+
+func stripJPEG(data []byte, payload []byte) ([]byte, error) {
+	if len(payload) > maxAPP1Payload {
+		return nil, ErrPayloadTooLarge
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	if len(payload) > 0 {
+		out = append(out, app1Segment(payload)...)
+	}
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xff {
+			return nil, ErrMalformedContainer
+		}
+		marker := data[pos+1]
+		if marker == 0xda {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return nil, ErrMalformedContainer
+		}
+		if marker != 0xe1 && marker != 0xed && marker != 0xfe {
+			out = append(out, data[pos:pos+2+length]...)
+		}
+		pos += 2 + length
+	}
+	return append(out, data[pos:]...), nil
+}
+
+func stripPNG(data []byte, payload []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:8]...)
+	pos := 8
+	for pos+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		if length < 0 || pos+12+length > len(data) {
+			return nil, ErrMalformedContainer
+		}
+		chunkType := string(data[pos+4 : pos+8])
+		switch chunkType {
+		case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+		default:
+			out = append(out, data[pos:pos+12+length]...)
+		}
+		if chunkType == "IHDR" && len(payload) > 0 {
+			out = append(out, pngChunk("eXIf", payload)...)
+		}
+		pos += 12 + length
+	}
+	return out, nil
+}
+
+func stripWebP(data []byte, payload []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:12]...)
+	extended := -1
+	pos := 12
+	for pos+8 <= len(data) {
+		chunkType := string(data[pos : pos+4])
+		length := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		end := pos + 8 + length + length%2
+		if length < 0 || end > len(data) {
+			return nil, ErrMalformedContainer
+		}
+		switch chunkType {
+		case "EXIF", "XMP ":
+		default:
+			if chunkType == "VP8X" {
+				extended = len(out)
+			}
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+	if extended >= 0 {
+		out[extended+8] &^= 0x08 | 0x04
+		if len(payload) > 0 {
+			out[extended+8] |= 0x08
+			chunk := make([]byte, 8, 8+len(payload)+1)
+			copy(chunk, "EXIF")
+			binary.LittleEndian.PutUint32(chunk[4:], uint32(len(payload)))
+			chunk = append(chunk, payload...)
+			if len(payload)%2 == 1 {
+				chunk = append(chunk, 0)
+			}
+			out = append(out, chunk...)
+		}
+	}
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
+
+func app1Segment(payload []byte) []byte {
+	segment := make([]byte, 4, 4+6+len(payload))
+	segment[0], segment[1] = 0xff, 0xe1
+	binary.BigEndian.PutUint16(segment[2:], uint16(2+6+len(payload)))
+	segment = append(segment, "Exif\x00\x00"...)
+	return append(segment, payload...)
+}
+
+func pngChunk(chunkType string, payload []byte) []byte {
+	chunk := make([]byte, 8, 12+len(payload))
+	binary.BigEndian.PutUint32(chunk, uint32(len(payload)))
+	copy(chunk[4:], chunkType)
+	chunk = append(chunk, payload...)
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, crc32.ChecksumIEEE(chunk[4:]))
+	return append(chunk, crc...)
+}
@@ -0,0 +1,199 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"reflect"
+	"testing"
+
+	"image-processor/internal/domain"
+)
+
+func sampleImage(t *testing.T, format domain.ImageFormat) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case domain.FormatJPEG:
+		err = jpeg.Encode(&buf, img, nil)
+	case domain.FormatPNG:
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatalf("failed to encode sample %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+func riffChunk(chunkType string, payload []byte) []byte {
+	chunk := make([]byte, 8, 8+len(payload)+1)
+	copy(chunk, chunkType)
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(len(payload)))
+	chunk = append(chunk, payload...)
+	if len(payload)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+func sampleWebP(payload []byte) []byte {
+	vp8x := make([]byte, 10)
+	vp8x[0] = 0x08 | 0x04
+	data := []byte("RIFF\x00\x00\x00\x00WEBP")
+	data = append(data, riffChunk("VP8X", vp8x)...)
+	data = append(data, riffChunk("VP8L", []byte{0x2f, 0x00, 0x00, 0x00, 0x00})...)
+	data = append(data, riffChunk("EXIF", append([]byte("Exif\x00\x00"), payload...))...)
+	data = append(data, riffChunk("XMP ", []byte("<x:xmpmeta/>"))...)
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(data)-8))
+	return data
+}
+
+func TestFilter(t *testing.T) {
+	d, err := Parse(samplePayload())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		keep []string
+		want []string
+	}{
+		{"keep nothing", nil, []string{}},
+		{"keep camera", []string{domain.MetadataCamera}, []string{domain.MetadataCamera}},
+		{"drop gps", []string{domain.MetadataCamera, domain.MetadataCopyright, domain.MetadataDateTime, domain.MetadataOrientation}, []string{domain.MetadataCamera, domain.MetadataCopyright, domain.MetadataDateTime, domain.MetadataOrientation}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := d.Filter(func(group string) bool {
+				for _, keep := range tt.keep {
+					if group == keep {
+						return true
+					}
+				}
+				return false
+			})
+			if got := filtered.Groups(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter().Groups() = %v, want %v", got, tt.want)
+			}
+			encoded := filtered.Encode()
+			if len(tt.want) == 0 {
+				if encoded != nil {
+					t.Errorf("Encode() = %d bytes, want nil", len(encoded))
+				}
+				return
+			}
+			reparsed, err := Parse(encoded)
+			if err != nil {
+				t.Fatalf("Parse(Encode()) error = %v", err)
+			}
+			if got := reparsed.Groups(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(Encode()).Groups() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrip(t *testing.T) {
+	payload := samplePayload()
+	d, err := Parse(payload)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	keptPayload := d.Filter(func(group string) bool { return group == domain.MetadataCopyright }).Encode()
+	withExif := func(format domain.ImageFormat) []byte {
+		data, ok := Embed(sampleImage(t, format), format, payload)
+		if !ok {
+			t.Fatalf("Embed() did not embed into %s", format)
+		}
+		return data
+	}
+	pngWithText := func() []byte {
+		data := withExif(domain.FormatPNG)
+		const ihdrEnd = 8 + 8 + 13 + 4
+		out := append([]byte(nil), data[:ihdrEnd]...)
+		out = append(out, pngChunk("tEXt", []byte("Comment\x00secret"))...)
+		return append(out, data[ihdrEnd:]...)
+	}
+	tests := []struct {
+		name       string
+		data       []byte
+		keep       []byte
+		wantGroups []string
+	}{
+		{"jpeg strip all", withExif(domain.FormatJPEG), nil, nil},
+		{"jpeg keep copyright", withExif(domain.FormatJPEG), keptPayload, []string{domain.MetadataCopyright}},
+		{"png strip all", pngWithText(), nil, nil},
+		{"png keep copyright", withExif(domain.FormatPNG), keptPayload, []string{domain.MetadataCopyright}},
+		{"webp strip all", sampleWebP(payload), nil, nil},
+		{"webp keep copyright", sampleWebP(payload), keptPayload, []string{domain.MetadataCopyright}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(tt.data); err != nil {
+				t.Fatalf("Read() before strip error = %v", err)
+			}
+			stripped, err := Strip(tt.data, tt.keep)
+			if err != nil {
+				t.Fatalf("Strip() error = %v", err)
+			}
+			for _, marker := range [][]byte{[]byte("EOS R5"), []byte("secret"), []byte("xmpmeta")} {
+				if bytes.Contains(stripped, marker) {
+					t.Errorf("Strip() left %q in the output", marker)
+				}
+			}
+			got, err := Read(stripped)
+			if tt.wantGroups == nil {
+				if !errors.Is(err, ErrNoExif) {
+					t.Errorf("Read() after strip error = %v, want %v", err, ErrNoExif)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() after strip error = %v", err)
+			}
+			if groups := got.Groups(); !reflect.DeepEqual(groups, tt.wantGroups) {
+				t.Errorf("Groups() after strip = %v, want %v", groups, tt.wantGroups)
+			}
+		})
+	}
+}
+
+func TestStripDecodesAfterStrip(t *testing.T) {
+	for _, format := range []domain.ImageFormat{domain.FormatJPEG, domain.FormatPNG} {
+		data, _ := Embed(sampleImage(t, format), format, samplePayload())
+		stripped, err := Strip(data, nil)
+		if err != nil {
+			t.Fatalf("Strip(%s) error = %v", format, err)
+		}
+		if _, _, err := image.Decode(bytes.NewReader(stripped)); err != nil {
+			t.Errorf("stripped %s does not decode: %v", format, err)
+		}
+	}
+}
+
+func TestStripErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		payload []byte
+		want    error
+	}{
+		{"gif", []byte("GIF89a\x01\x00\x01\x00"), nil, ErrUnsupportedContainer},
+		{"tiff", []byte("II*\x00\x08\x00\x00\x00"), nil, ErrUnsupportedContainer},
+		{"jpeg payload too large", []byte("\xff\xd8\xff\xd9"), make([]byte, maxAPP1Payload+1), ErrPayloadTooLarge},
+		{"jpeg truncated segment", []byte("\xff\xd8\xff\xe1\x10\x00"), nil, ErrMalformedContainer},
+		{"png truncated chunk", []byte("\x89PNG\r\n\x1a\n\x00\x00\x10\x00IHDR\x00\x00\x00\x00"), nil, ErrMalformedContainer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Strip(tt.data, tt.payload); !errors.Is(err, tt.want) {
+				t.Errorf("Strip() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
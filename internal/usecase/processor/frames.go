@@ -0,0 +1,104 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/usecase/processor/operations"
+)
+
+type frameSet struct {
+	frames   []image.Image
+	source   *gif.GIF
+	format   domain.ImageFormat
+	exif     []byte
+	crop     *domain.CropBox
+	redacted bool
+}
+
+func newFrameSet(data []byte, img image.Image, format domain.ImageFormat) *frameSet {
+	if format == domain.FormatGIF {
+		if anim, err := gif.DecodeAll(bytes.NewReader(data)); err == nil && len(anim.Image) > 1 {
+			return &frameSet{frames: compositeFrames(anim), source: anim, format: format}
+		}
+	}
+	return &frameSet{frames: []image.Image{img}, format: format}
+}
+
+func (f *frameSet) animated() bool {
+	return len(f.frames) > 1
+}
+
+func (f *frameSet) still(idx int) (*frameSet, error) {
+	if idx < 0 || idx >= len(f.frames) {
+		return nil, fmt.Errorf("frame %d out of range: image has %d frames", idx, len(f.frames))
+	}
+	return &frameSet{frames: []image.Image{f.frames[idx]}, format: f.format, exif: f.exif, redacted: f.redacted}, nil
+}
+
+func (f *frameSet) apply(ctx context.Context, op operations.Operation, params domain.Params) (*frameSet, error) {
+	if redact, ok := params.(*domain.RedactParams); ok && redact.ReplaceOriginal && f.redacted {
+		return f, nil
+	}
+	ctx, report := operations.WithReport(ctx)
+	processed := make([]image.Image, len(f.frames))
+	for idx, frame := range f.frames {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out, err := op.Apply(ctx, frame, params)
+		if err != nil {
+			if f.animated() {
+				return nil, fmt.Errorf("frame %d: %w", idx, err)
+			}
+			return nil, err
+		}
+		processed[idx] = out
+	}
+	result := &frameSet{frames: processed, source: f.source, format: f.format, exif: f.exif, redacted: f.redacted}
+	if rect, ok := report.Crop(); ok {
+		result.crop = &domain.CropBox{X: rect.Min.X, Y: rect.Min.Y, Width: rect.Dx(), Height: rect.Dy()}
+	} else if !op.Capabilities().ChangesDimensions {
+		result.crop = f.crop
+	}
+	return result, nil
+}
+
+func compositeFrames(anim *gif.GIF) []image.Image {
+	bounds := image.Rect(0, 0, anim.Config.Width, anim.Config.Height)
+	if bounds.Empty() {
+		bounds = anim.Image[0].Bounds()
+	}
+	canvas := image.NewRGBA(bounds)
+	frames := make([]image.Image, len(anim.Image))
+	for idx, frame := range anim.Image {
+		var disposal byte
+		if idx < len(anim.Disposal) {
+			disposal = anim.Disposal[idx]
+		}
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = cloneRGBA(canvas)
+		}
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		frames[idx] = cloneRGBA(canvas)
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+	return frames
+}
+
+func cloneRGBA(src *image.RGBA) *image.RGBA {
+	dst := image.NewRGBA(src.Bounds())
+	copy(dst.Pix, src.Pix)
+	return dst
+}
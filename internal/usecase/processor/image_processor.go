@@ -3,36 +3,48 @@ package processor
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
-	"image"
-	_ "image/gif"
-	_ "image/jpeg"
-	_ "image/png"
-	"io"
-	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"image-processor/internal/domain"
+	"image-processor/internal/usecase/processor/decoder"
+	"image-processor/internal/usecase/processor/encoder"
+	"image-processor/internal/usecase/processor/exif"
 	"image-processor/internal/usecase/processor/operations"
 
 	"github.com/wb-go/wbf/zlog"
 )
 
+var (
+	placeholderPattern = regexp.MustCompile(`\{[a-z_]+\}`)
+	unsafeSegmentChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
 type ImageProcessor struct {
-	resizer     *operations.Resizer
-	thumbnailer *operations.Thumbnailer
-	watermarker *operations.Watermarker
-	fileRepo    fileRepository
-	logger      *zlog.Zerolog
+	registry       *operations.Registry
+	decoders       *decoder.Registry
+	encoders       *encoder.Registry
+	fileRepo       fileRepository
+	originals      originalRepository
+	metadataPolicy domain.MetadataPolicy
+	kernel         domain.ResampleKernel
+	assets         assetCache
+	logger         *zlog.Zerolog
 }
 
-func NewImageProcessor(fileRepo fileRepository, logger *zlog.Zerolog) *ImageProcessor {
+func NewImageProcessor(fileRepo fileRepository, originals originalRepository, metadataPolicy domain.MetadataPolicy, kernel domain.ResampleKernel, logger *zlog.Zerolog) *ImageProcessor {
 	return &ImageProcessor{
-		resizer:     operations.NewResizer(),
-		thumbnailer: operations.NewThumbnailer(),
-		watermarker: operations.NewWatermarker(),
-		fileRepo:    fileRepo,
-		logger:      logger,
+		registry:       operations.Default(),
+		decoders:       decoder.Default(),
+		encoders:       encoder.Default(),
+		fileRepo:       fileRepo,
+		originals:      originals,
+		metadataPolicy: metadataPolicy,
+		kernel:         kernel,
+		logger:         logger,
 	}
 }
 
@@ -44,139 +56,423 @@ func (p *ImageProcessor) Process(ctx context.Context, task *domain.ProcessingTas
 		ProcessedPaths: make(map[string]string),
 		Error:          "",
 	}
-	img, format, err := image.Decode(bytes.NewReader(originalData))
+	ctx = p.operationContext(ctx)
+	frames, targetFormat, metadata, err := p.prepare(ctx, task, originalData)
 	if err != nil {
 		result.Status = domain.StatusFailed
 		result.Error = fmt.Sprintf("Failed to decode image: %v", err)
-		p.logger.Error().Err(err).Str("image_id", task.ImageID).Msg("Failed to decode image")
 		return result, fmt.Errorf("failed to decode image: %w", err)
 	}
-	targetFormat := string(task.Format)
-	if targetFormat == "" {
-		targetFormat = format
+	result.Metadata = metadata
+	if frames.redacted && !domain.IsRedactedOriginalPath(task.OriginalPath) {
+		if err := p.replaceOriginal(ctx, task, frames, metadata.Report); err != nil {
+			result.Status = domain.StatusFailed
+			result.Error = fmt.Sprintf("Failed to replace original: %v", err)
+			p.logger.Error().Err(err).Str("image_id", task.ImageID).Msg("Failed to replace original with redacted version")
+			return result, err
+		}
 	}
+	summarizeImage(metadata, frames.frames[0])
 	p.logger.Info().
 		Str("image_id", task.ImageID).
-		Str("original_format", format).
 		Str("target_format", targetFormat).
 		Int("operations", len(task.Operations)).
+		Int("frames", len(frames.frames)).
 		Msg("Starting image processing")
-	for _, operation := range task.Operations {
-		processedPath, processedData, err := p.applyOperation(ctx, task, img, targetFormat, operation)
+	if task.Pipeline {
+		if err := p.processPipeline(ctx, task, frames, targetFormat, result); err != nil {
+			return result, err
+		}
+	} else {
+		for _, operation := range task.Operations {
+			if op, ok := p.registry.Lookup(operation.Type); ok && op.Capabilities().MultiOutput {
+				if multi, ok := op.(operations.MultiOutputOperation); ok {
+					if err := p.processMultiOutput(ctx, task, frames, targetFormat, operation, multi, result); err != nil {
+						return result, err
+					}
+					continue
+				}
+			}
+			variant, encoded, err := p.applyOperation(ctx, task, frames, targetFormat, operation)
+			if err != nil {
+				result.Status = domain.StatusFailed
+				result.Error = fmt.Sprintf("Operation %s failed: %v", operation.Type, err)
+				p.logger.Error().
+					Err(err).
+					Str("image_id", task.ImageID).
+					Str("operation", string(operation.Type)).
+					Msg("Operation failed")
+				return result, fmt.Errorf("operation %s failed: %w", operation.Type, err)
+			}
+			if err := p.saveVariant(ctx, task, result, variant, encoded); err != nil {
+				return result, err
+			}
+			result.ProcessedPaths[string(operation.Type)] = variant.Path
+		}
+	}
+	p.logger.Info().
+		Str("image_id", task.ImageID).
+		Str("status", string(result.Status)).
+		Int("processed_operations", len(result.ProcessedPaths)).
+		Msg("Image processing completed")
+	return result, nil
+}
+
+func (p *ImageProcessor) Transform(ctx context.Context, task *domain.ProcessingTask, originalData []byte) (*domain.ProcessedVariant, []byte, error) {
+	if len(task.Operations) == 0 {
+		return nil, nil, fmt.Errorf("transform requires at least one operation")
+	}
+	if domain.ReplacesOriginal(task.Operations...) {
+		return nil, nil, fmt.Errorf("transform cannot replace the original image")
+	}
+	ctx = p.operationContext(ctx)
+	frames, targetFormat, _, err := p.prepare(ctx, task, originalData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	last := task.Operations[len(task.Operations)-1]
+	var (
+		variant domain.ProcessedVariant
+		encoded *encoder.Result
+	)
+	if len(task.Operations) == 1 {
+		if variant, encoded, err = p.applyOperation(ctx, task, frames, targetFormat, last); err != nil {
+			return nil, nil, fmt.Errorf("operation %s failed: %w", last.Type, err)
+		}
+	} else {
+		current, preservesGIF, err := p.applyChain(ctx, frames, task.Operations)
 		if err != nil {
+			return nil, nil, err
+		}
+		if encoded, err = p.encode(current, p.outputOptions(last.Encoding, targetFormat, preservesGIF)); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode transform result: %w", err)
+		}
+		variant = domain.ProcessedVariant{
+			Operation:  last.Type,
+			Parameters: domain.CanonicalParams(last.Parameters),
+			CropBox:    current.crop,
+		}
+	}
+	cacheKey := domain.TransformCacheKey(task.Operations...)
+	result := &domain.ProcessingResult{ID: task.ID, ImageID: task.ImageID, ProcessedPaths: make(map[string]string)}
+	variant.CacheKey = cacheKey
+	variant.Path = fmt.Sprintf("processed/transform/%s/%s.%s", task.ImageID, cacheKey, encoded.Format)
+	if len(task.Operations) > 1 {
+		steps, err := json.Marshal(task.Operations)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal transform steps: %w", err)
+		}
+		variant.Steps = string(steps)
+	}
+	if err := p.saveVariant(ctx, task, result, variant, encoded); err != nil {
+		return nil, nil, err
+	}
+	return &result.Variants[0], encoded.Data, nil
+}
+
+func (p *ImageProcessor) applyChain(ctx context.Context, frames *frameSet, chain []domain.OperationParams) (*frameSet, bool, error) {
+	current := frames
+	preservesGIF := true
+	for idx, operation := range chain {
+		op, ok := p.registry.Lookup(operation.Type)
+		if !ok || !op.Capabilities().Chainable {
+			return nil, false, fmt.Errorf("operation %s cannot be chained", operation.Type)
+		}
+		if !op.Capabilities().PreservesGIF {
+			preservesGIF = false
+			if current.animated() {
+				current, _ = current.still(0)
+			}
+		}
+		next, err := current.apply(ctx, op, operation.Parameters)
+		if err != nil {
+			return nil, false, fmt.Errorf("step %d (%s) failed: %w", idx, operation.Type, err)
+		}
+		current = next
+	}
+	return current, preservesGIF, nil
+}
+
+func (p *ImageProcessor) prepare(ctx context.Context, task *domain.ProcessingTask, originalData []byte) (*frameSet, string, *domain.ImageMetadata, error) {
+	img, format, err := p.decoders.Decode(originalData)
+	if err != nil {
+		p.logger.Error().Err(err).Str("image_id", task.ImageID).Msg("Failed to decode image")
+		return nil, "", nil, err
+	}
+	exifData, _ := exif.Read(originalData)
+	metadata := extractMetadata(img, exifData)
+	if task.AutoOrient && metadata.Orientation > 1 {
+		img = operations.Orient(img, metadata.Orientation)
+		p.logger.Debug().Str("image_id", task.ImageID).Int("orientation", metadata.Orientation).Msg("Applied EXIF orientation")
+	}
+	frames := newFrameSet(originalData, img, format)
+	metadata.FrameCount = len(frames.frames)
+	policy := task.Metadata.Resolve(p.metadataPolicy)
+	frames.exif, metadata.Report = p.applyMetadataPolicy(ctx, task, policy, originalData, exifData)
+	if frames, err = p.applyOriginalRedactions(ctx, task, frames, metadata.Report); err != nil {
+		return nil, "", nil, err
+	}
+	targetFormat := string(task.Format)
+	if targetFormat == "" {
+		targetFormat = string(format)
+	}
+	return frames, targetFormat, metadata, nil
+}
+
+func (p *ImageProcessor) processPipeline(ctx context.Context, task *domain.ProcessingTask, frames *frameSet, format string, result *domain.ProcessingResult) error {
+	current := frames
+	preservesGIF := true
+	last := len(task.Operations) - 1
+	outputs := make(map[string]bool)
+	for idx, operation := range task.Operations {
+		op, ok := p.registry.Lookup(operation.Type)
+		if !ok || !op.Capabilities().Chainable {
 			result.Status = domain.StatusFailed
-			result.Error = fmt.Sprintf("Operation %s failed: %v", operation.Type, err)
-			p.logger.Error().
-				Err(err).
-				Str("image_id", task.ImageID).
-				Str("operation", string(operation.Type)).
-				Msg("Operation failed")
-			return result, fmt.Errorf("operation %s failed: %w", operation.Type, err)
+			result.Error = fmt.Sprintf("Operation %s cannot be used in a pipeline", operation.Type)
+			return fmt.Errorf("operation %s cannot be used in a pipeline", operation.Type)
 		}
-		err = p.fileRepo.SaveProcessed(ctx, processedPath, bytes.NewReader(processedData), int64(len(processedData)), getContentType(processedPath))
+		if !op.Capabilities().PreservesGIF {
+			preservesGIF = false
+			if current.animated() {
+				current, _ = current.still(0)
+			}
+		}
+		next, err := current.apply(ctx, op, operation.Parameters)
 		if err != nil {
 			result.Status = domain.StatusFailed
-			result.Error = fmt.Sprintf("Failed to save processed image: %v", err)
+			result.Error = fmt.Sprintf("Pipeline step %d (%s) failed: %v", idx, operation.Type, err)
 			p.logger.Error().
 				Err(err).
 				Str("image_id", task.ImageID).
+				Int("step", idx).
 				Str("operation", string(operation.Type)).
-				Str("path", processedPath).
-				Msg("Failed to save processed image")
-			return result, fmt.Errorf("failed to save processed image: %w", err)
+				Msg("Pipeline step failed")
+			return fmt.Errorf("pipeline step %d (%s) failed: %w", idx, operation.Type, err)
+		}
+		current = next
+		output := operation.Output
+		if output == "" && idx == last {
+			output = domain.DefaultPipelineOutput
+		}
+		if output == "" {
+			continue
+		}
+		if !domain.IsValidVariantName(output) || outputs[output] {
+			result.Status = domain.StatusFailed
+			result.Error = fmt.Sprintf("Invalid or duplicate pipeline output name: %q", output)
+			return fmt.Errorf("invalid or duplicate pipeline output name: %q", output)
 		}
-		result.ProcessedPaths[string(operation.Type)] = processedPath
-		p.logger.Debug().
+		outputs[output] = true
+		encoded, err := p.encode(current, p.outputOptions(operation.Encoding, format, preservesGIF))
+		if err != nil {
+			result.Status = domain.StatusFailed
+			result.Error = fmt.Sprintf("Failed to encode pipeline output %s: %v", output, err)
+			return fmt.Errorf("failed to encode pipeline output %s: %w", output, err)
+		}
+		steps, err := json.Marshal(task.Operations[:idx+1])
+		if err != nil {
+			return fmt.Errorf("failed to marshal pipeline steps: %w", err)
+		}
+		variant := domain.ProcessedVariant{
+			Operation:  operation.Type,
+			Variant:    output,
+			Parameters: domain.CanonicalParams(operation.Parameters),
+			Steps:      string(steps),
+			CropBox:    current.crop,
+			Path:       fmt.Sprintf("processed/pipeline/%s/%s.%s", task.ImageID, output, encoded.Format),
+		}
+		if err := p.saveVariant(ctx, task, result, variant, encoded); err != nil {
+			return err
+		}
+		result.ProcessedPaths[output] = variant.Path
+	}
+	return nil
+}
+
+func (p *ImageProcessor) saveVariant(ctx context.Context, task *domain.ProcessingTask, result *domain.ProcessingResult, variant domain.ProcessedVariant, encoded *encoder.Result) error {
+	data := encoded.Data
+	err := p.fileRepo.SaveProcessed(ctx, variant.Path, bytes.NewReader(data), int64(len(data)), encoded.MimeType)
+	if err != nil {
+		result.Status = domain.StatusFailed
+		result.Error = fmt.Sprintf("Failed to save processed image: %v", err)
+		p.logger.Error().
+			Err(err).
 			Str("image_id", task.ImageID).
-			Str("operation", string(operation.Type)).
-			Str("path", processedPath).
-			Int("size", len(processedData)).
-			Msg("Operation completed and saved")
+			Str("operation", string(variant.Operation)).
+			Str("path", variant.Path).
+			Msg("Failed to save processed image")
+		return fmt.Errorf("failed to save processed image: %w", err)
 	}
-	p.logger.Info().
+	variant.Size = int64(len(data))
+	variant.MimeType = encoded.MimeType
+	variant.Format = encoded.Format
+	result.Variants = append(result.Variants, variant)
+	p.logger.Debug().
 		Str("image_id", task.ImageID).
-		Str("status", string(result.Status)).
-		Int("processed_operations", len(result.ProcessedPaths)).
-		Msg("Image processing completed")
-	return result, nil
+		Str("operation", string(variant.Operation)).
+		Str("variant", variant.Variant).
+		Str("path", variant.Path).
+		Int("size", len(data)).
+		Msg("Operation completed and saved")
+	return nil
+}
+
+func (p *ImageProcessor) applyOperation(ctx context.Context, task *domain.ProcessingTask, frames *frameSet, format string, operation domain.OperationParams) (domain.ProcessedVariant, *encoder.Result, error) {
+	op, ok := p.registry.Lookup(operation.Type)
+	if !ok {
+		return domain.ProcessedVariant{}, nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+	}
+	opts := p.outputOptions(operation.Encoding, format, op.Capabilities().PreservesGIF)
+	source, err := sourceFrames(frames, opts)
+	if err != nil {
+		return domain.ProcessedVariant{}, nil, err
+	}
+	processed, err := source.apply(ctx, op, operation.Parameters)
+	if err != nil {
+		return domain.ProcessedVariant{}, nil, fmt.Errorf("failed to process operation %s: %w", operation.Type, err)
+	}
+	encoded, err := p.encode(processed, opts)
+	if err != nil {
+		return domain.ProcessedVariant{}, nil, fmt.Errorf("failed to encode %s result: %w", operation.Type, err)
+	}
+	return domain.ProcessedVariant{
+		Operation:  operation.Type,
+		Parameters: domain.CanonicalParams(operation.Parameters),
+		CropBox:    processed.crop,
+		Path:       p.generatePath(task.ImageID, op, string(encoded.Format), domain.OperationParams{Type: operation.Type, Parameters: operation.Parameters, Encoding: opts}),
+	}, encoded, nil
 }
 
-func (p *ImageProcessor) applyOperation(ctx context.Context, task *domain.ProcessingTask, img image.Image, format string, operation domain.OperationParams) (string, []byte, error) {
-	var processedData io.Reader
-	var processedFormat string
-	var err error
-	switch operation.Type {
-	case domain.OpResize:
-		processedData, processedFormat, err = p.resizer.Process(ctx, img, format, operation.Parameters)
-	case domain.OpThumbnail:
-		processedData, processedFormat, err = p.thumbnailer.Process(ctx, img, format, operation.Parameters)
-	case domain.OpWatermark:
-		processedData, processedFormat, err = p.watermarker.Process(ctx, img, format, operation.Parameters)
-	default:
-		return "", nil, fmt.Errorf("unsupported operation type: %s", operation.Type)
+func (p *ImageProcessor) processMultiOutput(ctx context.Context, task *domain.ProcessingTask, frames *frameSet, format string, operation domain.OperationParams, op operations.MultiOutputOperation, result *domain.ProcessingResult) error {
+	fail := func(err error) error {
+		result.Status = domain.StatusFailed
+		result.Error = fmt.Sprintf("Operation %s failed: %v", operation.Type, err)
+		p.logger.Error().
+			Err(err).
+			Str("image_id", task.ImageID).
+			Str("operation", string(operation.Type)).
+			Msg("Operation failed")
+		return fmt.Errorf("operation %s failed: %w", operation.Type, err)
 	}
+	opts := p.outputOptions(operation.Encoding, format, op.Capabilities().PreservesGIF)
+	source, err := sourceFrames(frames, opts)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to process operation %s: %w", operation.Type, err)
+		return fail(err)
 	}
-	data, err := io.ReadAll(processedData)
+	outputs, err := op.Outputs(source.frames[0].Bounds(), operation.Parameters)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to read processed data: %w", err)
+		return fail(err)
+	}
+	paramsHash := domain.TransformCacheKey(domain.OperationParams{Type: operation.Type, Parameters: operation.Parameters, Encoding: opts})
+	var sizes string
+	if params, ok := operation.Parameters.(*domain.ResponsiveParams); ok {
+		sizes = params.Sizes
 	}
-	path := p.generatePath(task.ImageID, operation.Type, processedFormat, operation.Parameters)
-	return path, data, nil
+	for _, output := range outputs {
+		inner, ok := p.registry.Lookup(output.Operation)
+		if !ok {
+			return fail(fmt.Errorf("unsupported operation type: %s", output.Operation))
+		}
+		processed, err := source.apply(ctx, inner, output.Params)
+		if err != nil {
+			return fail(fmt.Errorf("output %s: %w", output.Name, err))
+		}
+		encoded, err := p.encode(processed, opts)
+		if err != nil {
+			return fail(fmt.Errorf("failed to encode output %s: %w", output.Name, err))
+		}
+		bounds := processed.frames[0].Bounds()
+		parameters, err := json.Marshal(domain.ResponsiveOutput{
+			Width:      bounds.Dx(),
+			Height:     bounds.Dy(),
+			Descriptor: output.Descriptor,
+			Sizes:      sizes,
+		})
+		if err != nil {
+			return fail(err)
+		}
+		variant := domain.ProcessedVariant{
+			Operation:  operation.Type,
+			Variant:    domain.ResponsiveVariantName(output.Name, encoded.Format),
+			Parameters: string(parameters),
+			Path:       fmt.Sprintf("processed/%s/%s/%s-%s.%s", sanitizeSegment(string(operation.Type)), task.ImageID, sanitizeSegment(output.Name), paramsHash, encoded.Format),
+		}
+		if err := p.saveVariant(ctx, task, result, variant, encoded); err != nil {
+			return err
+		}
+		result.ProcessedPaths[variant.Variant] = variant.Path
+	}
+	return nil
 }
 
-func (p *ImageProcessor) generatePath(imageID string, operation domain.OperationType, format string, params map[string]interface{}) string {
-	basePath := "processed/"
-	switch operation {
-	case domain.OpResize:
-		var width int
-		if w, ok := params["width"].(float64); ok {
-			width = int(w)
-		} else if w, ok := params["width"].(int); ok {
-			width = w
-		}
-		var height int
-		if h, ok := params["height"].(float64); ok {
-			height = int(h)
-		} else if h, ok := params["height"].(int); ok {
-			height = h
-		}
-		return fmt.Sprintf("%sresize/%s/%dx%d.%s", basePath, imageID, width, height, format)
-	case domain.OpThumbnail:
-		var size int
-		if s, ok := params["size"].(float64); ok {
-			size = int(s)
-		} else if s, ok := params["size"].(int); ok {
-			size = s
-		}
-		if size == 0 {
-			size = domain.DefaultThumbnailSize
-		}
-		return fmt.Sprintf("%sthumbnails/%s/%d.%s", basePath, imageID, size, format)
-	case domain.OpWatermark:
-		return fmt.Sprintf("%swatermarked/%s/watermarked.%s", basePath, imageID, format)
-	default:
-		return fmt.Sprintf("%s%s/%s/processed.%s", basePath, strings.ToLower(string(operation)), imageID, format)
+func sourceFrames(frames *frameSet, opts domain.OutputOptions) (*frameSet, error) {
+	if !frames.animated() || (opts.Frame == nil && opts.Format == domain.FormatGIF) {
+		return frames, nil
 	}
+	frame := 0
+	if opts.Frame != nil {
+		frame = *opts.Frame
+	}
+	return frames.still(frame)
 }
 
-func getContentType(path string) string {
-	ext := strings.ToLower(filepath.Ext(path))
-	switch ext {
-	case ".jpg", ".jpeg":
-		return "image/jpeg"
-	case ".png":
-		return "image/png"
-	case ".gif":
-		return "image/gif"
-	case ".webp":
-		return "image/webp"
-	case ".bmp":
-		return "image/bmp"
-	case ".tiff", ".tif":
-		return "image/tiff"
-	default:
-		return "image/jpeg"
+func (p *ImageProcessor) encode(frames *frameSet, opts domain.OutputOptions) (*encoder.Result, error) {
+	if opts.Frame != nil && frames.animated() {
+		var err error
+		if frames, err = frames.still(*opts.Frame); err != nil {
+			return nil, err
+		}
+	}
+	if frames.animated() && opts.Format == domain.FormatGIF {
+		return p.encoders.EncodeAnimation(frames.frames, frames.source, opts)
 	}
+	encoded, err := p.encoders.Encode(frames.frames[0], opts)
+	if err != nil {
+		return nil, err
+	}
+	encoded.Data, _ = exif.Embed(encoded.Data, encoded.Format, frames.exif)
+	return encoded, nil
+}
+
+func (p *ImageProcessor) outputOptions(opts domain.OutputOptions, sourceFormat string, preservesGIF bool) domain.OutputOptions {
+	if opts.Format != "" {
+		return opts
+	}
+	opts.Format = domain.NormalizeFormat(sourceFormat)
+	if (opts.Format == domain.FormatGIF && !preservesGIF) || !p.encoders.CanEncode(opts.Format) {
+		opts.Format = domain.FormatJPEG
+	}
+	return opts
+}
+
+func (p *ImageProcessor) generatePath(imageID string, op operations.Operation, format string, operation domain.OperationParams) string {
+	template := op.PathTemplate()
+	if template == "" {
+		template = "{operation}/{image_id}/processed.{format}"
+	}
+	values := map[string]string{
+		"operation": strings.ToLower(string(op.Name())),
+		"image_id":  imageID,
+		"format":    format,
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(domain.CanonicalParams(operation.Parameters)), &fields); err == nil {
+		for name, value := range fields {
+			switch v := value.(type) {
+			case float64:
+				values[name] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				values[name] = fmt.Sprint(v)
+			}
+		}
+	}
+	values["params_hash"] = domain.TransformCacheKey(operation)
+	path := placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+		return sanitizeSegment(values[match[1:len(match)-1]])
+	})
+	return "processed/" + path
+}
+
+func sanitizeSegment(value string) string {
+	return unsafeSegmentChars.ReplaceAllString(value, "_")
 }
@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/usecase/processor/operations"
+)
+
+func TestGeneratePathIsUniquePerVariant(t *testing.T) {
+	p := &ImageProcessor{registry: operations.Default()}
+	const imageID = "550e8400-e29b-41d4-a716-446655440000"
+	operation := func(t *testing.T, op domain.OperationType, raw string, encoding domain.OutputOptions) domain.OperationParams {
+		t.Helper()
+		params, err := domain.DecodeParams(op, json.RawMessage(raw))
+		if err != nil {
+			t.Fatalf("DecodeParams(%s, %s) error = %v", op, raw, err)
+		}
+		return domain.OperationParams{Type: op, Parameters: params, Encoding: encoding}
+	}
+	quality := func(q int) domain.OutputOptions { return domain.OutputOptions{Quality: q} }
+	tests := []struct {
+		name string
+		op   domain.OperationType
+		a, b string
+		encA domain.OutputOptions
+		encB domain.OutputOptions
+		dir  string
+	}{
+		{"resize gravity", domain.OpResize, `{"width":300,"height":200,"fit":"cover"}`, `{"width":300,"height":200,"fit":"cover","gravity":"smart"}`, quality(85), quality(85), "resize/"},
+		{"resize kernel", domain.OpResize, `{"width":300,"height":200}`, `{"width":300,"height":200,"kernel":"nearest"}`, quality(85), quality(85), "resize/"},
+		{"resize no upscale", domain.OpResize, `{"width":300,"height":200,"fit":"inside"}`, `{"width":300,"height":200,"fit":"inside","no_upscale":true}`, quality(85), quality(85), "resize/"},
+		{"resize quality", domain.OpResize, `{"width":300,"height":200}`, `{"width":300,"height":200}`, quality(85), quality(60), "resize/"},
+		{"thumbnail background", domain.OpThumbnail, `{"size":200,"fit":"contain"}`, `{"size":200,"fit":"contain","background":"0,0,0"}`, quality(85), quality(85), "thumbnails/"},
+		{"rotate background", domain.OpRotate, `{"angle":45}`, `{"angle":45,"background":"0,0,0"}`, quality(85), quality(85), "rotate/"},
+		{"crop quality", domain.OpCrop, `{"x":0,"y":0,"width":10,"height":10}`, `{"x":0,"y":0,"width":10,"height":10}`, quality(85), quality(50), "crop/"},
+		{"flip compression", domain.OpFlip, `{"direction":"horizontal"}`, `{"direction":"horizontal"}`, domain.OutputOptions{}, domain.OutputOptions{PNGCompression: domain.PNGCompressionBest}, "flip/"},
+		{"grayscale quality", domain.OpGrayscale, `{}`, `{}`, quality(70), quality(80), "grayscale/"},
+		{"adjust brightness", domain.OpAdjust, `{"brightness":0.1}`, `{"brightness":0.2}`, quality(85), quality(85), "adjusted/"},
+		{"filter radius", domain.OpFilter, `{"filter":"gaussian-blur","radius":2}`, `{"filter":"gaussian-blur","radius":3}`, quality(85), quality(85), "filtered/"},
+		{"redact color", domain.OpRedact, `{"regions":[{"x":0,"y":0,"width":10,"height":10}],"mode":"fill","color":"0,0,0"}`, `{"regions":[{"x":0,"y":0,"width":10,"height":10}],"mode":"fill","color":"255,0,0"}`, quality(85), quality(85), "redacted/"},
+		{"watermark text", domain.OpWatermark, `{"text":"one"}`, `{"text":"two"}`, quality(85), quality(85), "watermarked/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, ok := p.registry.Lookup(tt.op)
+			if !ok {
+				t.Fatalf("operation %s is not registered", tt.op)
+			}
+			first := p.generatePath(imageID, op, "jpeg", operation(t, tt.op, tt.a, tt.encA))
+			second := p.generatePath(imageID, op, "jpeg", operation(t, tt.op, tt.b, tt.encB))
+			if first == second {
+				t.Errorf("generatePath() = %q for both variants", first)
+			}
+			again := p.generatePath(imageID, op, "jpeg", operation(t, tt.op, tt.a, tt.encA))
+			if again != first {
+				t.Errorf("generatePath() is not stable: %q then %q", first, again)
+			}
+			for _, path := range []string{first, second} {
+				if !strings.HasPrefix(path, "processed/"+tt.dir+imageID+"/") || !strings.HasSuffix(path, ".jpeg") {
+					t.Errorf("generatePath() = %q, want processed/%s%s/*.jpeg", path, tt.dir, imageID)
+				}
+				if strings.ContainsAny(path, "{}") {
+					t.Errorf("generatePath() = %q left a placeholder unresolved", path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"image"
+	"math/bits"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/usecase/processor/exif"
+	"image-processor/internal/usecase/processor/operations"
+	"image-processor/internal/usecase/processor/palette"
+	"image-processor/internal/usecase/processor/placeholder"
+)
+
+func extractMetadata(img image.Image, exifData *exif.Data) *domain.ImageMetadata {
+	bounds := img.Bounds()
+	colorModel, bitDepth := describeColorModel(img)
+	metadata := &domain.ImageMetadata{
+		Width:      bounds.Dx(),
+		Height:     bounds.Dy(),
+		ColorModel: colorModel,
+		BitDepth:   bitDepth,
+		FrameCount: 1,
+	}
+	if exifData == nil {
+		return metadata
+	}
+	metadata.Orientation = exifData.Orientation()
+	info := &domain.ExifMetadata{}
+	info.CameraMake, info.CameraModel = exifData.Camera()
+	info.LensModel, _ = exifData.String(exif.ExifIFD, exif.TagLensModel)
+	info.Software, _ = exifData.String(exif.IFD0, exif.TagSoftware)
+	if takenAt, ok := exifData.TakenAt(); ok {
+		info.TakenAt = &takenAt
+	}
+	if lat, lon, ok := exifData.GPS(); ok {
+		info.GPS = &domain.GPSCoordinates{Latitude: lat, Longitude: lon}
+		if alt, ok := exifData.Altitude(); ok {
+			info.GPS.Altitude = &alt
+		}
+	}
+	if *info != (domain.ExifMetadata{}) {
+		metadata.Exif = info
+	}
+	return metadata
+}
+
+func summarizeImage(metadata *domain.ImageMetadata, img image.Image) {
+	small := operations.Downscale(img, placeholder.MaxSize)
+	metadata.BlurHash = placeholder.BlurHash(small)
+	metadata.ThumbHash = placeholder.ThumbHash(small)
+	metadata.Palette, metadata.AverageColor = palette.Extract(small, domain.DefaultPaletteSize)
+}
+
+func describeColorModel(img image.Image) (string, int) {
+	switch m := img.(type) {
+	case *image.YCbCr:
+		return "ycbcr", 8
+	case *image.NYCbCrA:
+		return "ycbcra", 8
+	case *image.RGBA:
+		return "rgba", 8
+	case *image.NRGBA:
+		return "nrgba", 8
+	case *image.RGBA64:
+		return "rgba", 16
+	case *image.NRGBA64:
+		return "nrgba", 16
+	case *image.Gray:
+		return "gray", 8
+	case *image.Gray16:
+		return "gray", 16
+	case *image.CMYK:
+		return "cmyk", 8
+	case *image.Paletted:
+		depth := bits.Len(uint(len(m.Palette) - 1))
+		if depth == 0 {
+			depth = 1
+		}
+		return "paletted", depth
+	default:
+		return "unknown", 8
+	}
+}
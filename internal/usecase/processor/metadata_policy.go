@@ -0,0 +1,92 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/usecase/processor/exif"
+)
+
+func (p *ImageProcessor) applyMetadataPolicy(ctx context.Context, task *domain.ProcessingTask, policy domain.MetadataPolicy, originalData []byte, exifData *exif.Data) ([]byte, *domain.MetadataReport) {
+	report := &domain.MetadataReport{Removed: []string{}, Kept: []string{}}
+	oriented := task.AutoOrient
+	keepForVariants := func(group string) bool {
+		if group == domain.MetadataOrientation && oriented {
+			return false
+		}
+		return policy.Keeps(group)
+	}
+	var variantExif []byte
+	if exifData != nil {
+		for _, group := range exifData.Groups() {
+			if keepForVariants(group) {
+				report.Kept = append(report.Kept, group)
+			} else {
+				report.Removed = append(report.Removed, group)
+			}
+		}
+		variantExif = exifData.Filter(keepForVariants).Encode()
+	}
+	if policy.StripOriginal {
+		var originalExif []byte
+		if exifData != nil {
+			originalExif = exifData.Filter(policy.Keeps).Encode()
+		}
+		scrubbed, err := exif.Strip(originalData, originalExif)
+		if errors.Is(err, exif.ErrPayloadTooLarge) {
+			p.logger.Warn().
+				Err(err).
+				Str("image_id", task.ImageID).
+				Int("payload_size", len(originalExif)).
+				Msg("Kept metadata is too large for the original, stripping all metadata from it")
+			scrubbed, err = exif.Strip(originalData, nil)
+		}
+		if errors.Is(err, exif.ErrUnsupportedContainer) {
+			p.logger.Warn().
+				Str("image_id", task.ImageID).
+				Msg("Original container cannot be scrubbed in place, re-encoding it without metadata")
+			scrubbed, err = p.reencodeOriginal(originalData)
+		}
+		if err != nil {
+			p.logger.Error().Err(err).Str("image_id", task.ImageID).Msg("Failed to scrub original metadata")
+			return variantExif, report
+		}
+		mimeType := "application/octet-stream"
+		if format, ok := p.decoders.Sniff(scrubbed); ok {
+			mimeType = format.MimeType
+		}
+		if err := p.fileRepo.SaveProcessed(ctx, task.OriginalPath, bytes.NewReader(scrubbed), int64(len(scrubbed)), mimeType); err != nil {
+			p.logger.Error().Err(err).Str("image_id", task.ImageID).Str("path", task.OriginalPath).Msg("Failed to save scrubbed original")
+			return variantExif, report
+		}
+		if err := p.originals.UpdateOriginal(ctx, task.ImageID, task.OriginalPath, int64(len(scrubbed)), mimeType); err != nil {
+			p.logger.Error().Err(err).Str("image_id", task.ImageID).Str("path", task.OriginalPath).Msg("Failed to persist scrubbed original size")
+		}
+		report.OriginalScrubbed = true
+		report.OriginalSize = int64(len(scrubbed))
+		p.logger.Info().
+			Str("image_id", task.ImageID).
+			Int("original_size", len(originalData)).
+			Int("scrubbed_size", len(scrubbed)).
+			Msg("Original image metadata scrubbed")
+	}
+	return variantExif, report
+}
+
+func (p *ImageProcessor) reencodeOriginal(originalData []byte) ([]byte, error) {
+	img, format, err := p.decoders.Decode(originalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode original: %w", err)
+	}
+	if !p.encoders.CanEncode(format) {
+		return nil, fmt.Errorf("%w: %s", exif.ErrUnsupportedContainer, format)
+	}
+	encoded, err := p.encode(newFrameSet(originalData, img, format), domain.OutputOptions{Format: format})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode original: %w", err)
+	}
+	return encoded.Data, nil
+}
@@ -0,0 +1,268 @@
+package operations
+
+import (
+	"context"
+	"image"
+	"math"
+
+	"image-processor/internal/domain"
+)
+
+const (
+	levelsClipFraction = 0.005
+	minChannelGain     = 0.25
+	maxChannelGain     = 4
+)
+
+type Adjuster struct{}
+
+func NewAdjuster() *Adjuster {
+	return &Adjuster{}
+}
+
+func (a *Adjuster) Name() domain.OperationType {
+	return domain.OpAdjust
+}
+
+func (a *Adjuster) NewParams() domain.Params {
+	return &domain.AdjustParams{Gamma: domain.DefaultGamma}
+}
+
+func (a *Adjuster) PathTemplate() string {
+	return "adjusted/{image_id}/{params_hash}.{format}"
+}
+
+func (a *Adjuster) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Chainable:         true,
+		PreservesGIF:      true,
+		ChangesDimensions: false,
+	}
+}
+
+func (a *Adjuster) Apply(ctx context.Context, img image.Image, params domain.Params) (image.Image, error) {
+	p, err := castParams[*domain.AdjustParams](params)
+	if err != nil {
+		return nil, err
+	}
+	return adjustImage(ctx, img, p)
+}
+
+type channelHistogram [3][256]int
+
+func adjustImage(ctx context.Context, img image.Image, p *domain.AdjustParams) (image.Image, error) {
+	dst := cloneRGBA(img)
+	gains := [3]float64{1, 1, 1}
+	low, high := 0.0, 1.0
+	if p.WhiteBalance != domain.WhiteBalanceNone || p.AutoLevels {
+		hist := histogram(dst)
+		gains = whiteBalanceGains(hist, p.WhiteBalance)
+		if p.AutoLevels {
+			low, high = levelBounds(hist, gains)
+		}
+	}
+	curves := toneCurves(p, gains, low, high)
+	var opaque [3][256]uint8
+	for c := range curves {
+		for v, value := range curves[c] {
+			opaque[c][v] = premultiply(value, 255)
+		}
+	}
+	matrix, mixing := colorMatrix(p.Saturation, p.Hue)
+	width := dst.Bounds().Dx()
+	err := parallelize(ctx, dst.Bounds().Dy(), func(lo, hi int) {
+		for y := lo; y < hi; y++ {
+			row := dst.Pix[y*dst.Stride : y*dst.Stride+4*width]
+			for i := 0; i < len(row); i += 4 {
+				alpha := row[i+3]
+				if alpha == 0 {
+					continue
+				}
+				if alpha == 255 && !mixing {
+					row[i], row[i+1], row[i+2] = opaque[0][row[i]], opaque[1][row[i+1]], opaque[2][row[i+2]]
+					continue
+				}
+				var rgb [3]float64
+				for c := 0; c < 3; c++ {
+					rgb[c] = curves[c][unpremultiply(row[i+c], alpha)]
+				}
+				if mixing {
+					rgb = matrix.apply(rgb)
+				}
+				for c := 0; c < 3; c++ {
+					row[i+c] = premultiply(rgb[c], alpha)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+func histogram(img *image.RGBA) *channelHistogram {
+	var hist channelHistogram
+	width := img.Bounds().Dx()
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+4*width]
+		for i := 0; i < len(row); i += 4 {
+			alpha := row[i+3]
+			if alpha == 0 {
+				continue
+			}
+			for c := 0; c < 3; c++ {
+				hist[c][unpremultiply(row[i+c], alpha)]++
+			}
+		}
+	}
+	return &hist
+}
+
+func (h *channelHistogram) percentile(channel int, fraction float64) float64 {
+	total := 0
+	for _, count := range h[channel] {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	target := int(math.Ceil(fraction * float64(total)))
+	seen := 0
+	for value, count := range h[channel] {
+		seen += count
+		if seen >= target && seen > 0 {
+			return float64(value)
+		}
+	}
+	return 255
+}
+
+func (h *channelHistogram) mean(channel int) float64 {
+	total, sum := 0, 0
+	for value, count := range h[channel] {
+		total += count
+		sum += value * count
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(sum) / float64(total)
+}
+
+func whiteBalanceGains(hist *channelHistogram, mode domain.WhiteBalance) [3]float64 {
+	gains := [3]float64{1, 1, 1}
+	var reference [3]float64
+	switch mode {
+	case domain.WhiteBalanceGrayWorld:
+		for c := range reference {
+			reference[c] = hist.mean(c)
+		}
+		gray := (reference[0] + reference[1] + reference[2]) / 3
+		for c := range gains {
+			if reference[c] > 0 {
+				gains[c] = gray / reference[c]
+			}
+		}
+	case domain.WhiteBalanceWhitePatch:
+		for c := range gains {
+			if white := hist.percentile(c, 1-levelsClipFraction); white > 0 {
+				gains[c] = 255 / white
+			}
+		}
+	}
+	for c := range gains {
+		gains[c] = math.Max(minChannelGain, math.Min(maxChannelGain, gains[c]))
+	}
+	return gains
+}
+
+func levelBounds(hist *channelHistogram, gains [3]float64) (float64, float64) {
+	low, high := 1.0, 0.0
+	for c := 0; c < 3; c++ {
+		low = math.Min(low, math.Min(1, gains[c]*hist.percentile(c, levelsClipFraction)/255))
+		high = math.Max(high, math.Min(1, gains[c]*hist.percentile(c, 1-levelsClipFraction)/255))
+	}
+	if high-low < 1.0/255 {
+		return 0, 1
+	}
+	return low, high
+}
+
+func toneCurves(p *domain.AdjustParams, gains [3]float64, low, high float64) [3][256]float64 {
+	var curves [3][256]float64
+	contrast := math.Pow(4, p.Contrast)
+	gamma := p.Gamma
+	if gamma <= 0 {
+		gamma = domain.DefaultGamma
+	}
+	for c := 0; c < 3; c++ {
+		for v := 0; v < 256; v++ {
+			x := math.Min(1, float64(v)/255*gains[c])
+			x = (x - low) / (high - low)
+			x += p.Brightness
+			x = (x-0.5)*contrast + 0.5
+			x = math.Max(0, math.Min(1, x))
+			curves[c][v] = math.Pow(x, 1/gamma)
+		}
+	}
+	return curves
+}
+
+type colorMat [3][3]float64
+
+func (m colorMat) apply(rgb [3]float64) [3]float64 {
+	var out [3]float64
+	for r := 0; r < 3; r++ {
+		out[r] = m[r][0]*rgb[0] + m[r][1]*rgb[1] + m[r][2]*rgb[2]
+	}
+	return out
+}
+
+func (m colorMat) mul(o colorMat) colorMat {
+	var out colorMat
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			out[r][c] = m[r][0]*o[0][c] + m[r][1]*o[1][c] + m[r][2]*o[2][c]
+		}
+	}
+	return out
+}
+
+func colorMatrix(saturation, hue float64) (colorMat, bool) {
+	if saturation == 0 && hue == 0 {
+		return colorMat{}, false
+	}
+	s := 1 + saturation
+	saturate := colorMat{
+		{0.213 + 0.787*s, 0.715 - 0.715*s, 0.072 - 0.072*s},
+		{0.213 - 0.213*s, 0.715 + 0.285*s, 0.072 - 0.072*s},
+		{0.213 - 0.213*s, 0.715 - 0.715*s, 0.072 + 0.928*s},
+	}
+	rad := hue * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	rotate := colorMat{
+		{0.213 + 0.787*cos - 0.213*sin, 0.715 - 0.715*cos - 0.715*sin, 0.072 - 0.072*cos + 0.928*sin},
+		{0.213 - 0.213*cos + 0.143*sin, 0.715 + 0.285*cos + 0.140*sin, 0.072 - 0.072*cos - 0.283*sin},
+		{0.213 - 0.213*cos - 0.787*sin, 0.715 - 0.715*cos + 0.715*sin, 0.072 + 0.928*cos + 0.072*sin},
+	}
+	return saturate.mul(rotate), true
+}
+
+func unpremultiply(value, alpha uint8) uint8 {
+	if alpha == 255 {
+		return value
+	}
+	return uint8(min(255, (int(value)*255+int(alpha)/2)/int(alpha)))
+}
+
+func premultiply(value float64, alpha uint8) uint8 {
+	scaled := value*float64(alpha) + 0.5
+	switch {
+	case scaled <= 0:
+		return 0
+	case scaled >= float64(alpha):
+		return alpha
+	}
+	return uint8(scaled)
+}
@@ -0,0 +1,24 @@
+package operations
+
+import (
+	"context"
+	"image"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+type AssetLoader interface {
+	LoadAsset(ctx context.Context, id string) (image.Image, error)
+	LoadFont(ctx context.Context, id string) (*sfnt.Font, error)
+}
+
+type assetContextKey struct{}
+
+func WithAssetLoader(ctx context.Context, loader AssetLoader) context.Context {
+	return context.WithValue(ctx, assetContextKey{}, loader)
+}
+
+func assetLoaderFrom(ctx context.Context) (AssetLoader, bool) {
+	loader, ok := ctx.Value(assetContextKey{}).(AssetLoader)
+	return loader, ok && loader != nil
+}
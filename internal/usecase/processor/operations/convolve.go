@@ -0,0 +1,312 @@
+package operations
+
+import (
+	"context"
+	"image"
+	"math"
+)
+
+const (
+	exactGaussianMaxSigma = 2
+	gaussianBoxPasses     = 3
+)
+
+func boxBlur(ctx context.Context, src *image.RGBA, radius int) (*image.RGBA, error) {
+	tmp := image.NewRGBA(src.Rect)
+	if err := boxBlurRows(ctx, src, tmp, radius); err != nil {
+		return nil, err
+	}
+	dst := image.NewRGBA(src.Rect)
+	if err := boxBlurColumns(ctx, tmp, dst, radius); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+func boxBlurRows(ctx context.Context, src, dst *image.RGBA, radius int) error {
+	width := src.Rect.Dx()
+	div := newDivider(2*radius + 1)
+	return parallelize(ctx, src.Rect.Dy(), func(lo, hi int) {
+		for y := lo; y < hi; y++ {
+			in := src.Pix[y*src.Stride : y*src.Stride+4*width]
+			out := dst.Pix[y*dst.Stride : y*dst.Stride+4*width]
+			var sums [4]int
+			for k := -radius; k <= radius; k++ {
+				px := in[4*clampIndex(k, width):]
+				sums[0], sums[1], sums[2], sums[3] = sums[0]+int(px[0]), sums[1]+int(px[1]), sums[2]+int(px[2]), sums[3]+int(px[3])
+			}
+			for x := 0; x < width; x++ {
+				px := out[4*x : 4*x+4]
+				px[0], px[1], px[2], px[3] = div.apply(sums[0]), div.apply(sums[1]), div.apply(sums[2]), div.apply(sums[3])
+				add := in[4*clampIndex(x+radius+1, width):]
+				sub := in[4*clampIndex(x-radius, width):]
+				for c := range sums {
+					sums[c] += int(add[c]) - int(sub[c])
+				}
+			}
+		}
+	})
+}
+
+func boxBlurColumns(ctx context.Context, src, dst *image.RGBA, radius int) error {
+	height := src.Rect.Dy()
+	div := newDivider(2*radius + 1)
+	return parallelize(ctx, src.Rect.Dx(), func(lo, hi int) {
+		sums := make([]int, 4*(hi-lo))
+		for k := -radius; k <= radius; k++ {
+			row := src.Pix[clampIndex(k, height)*src.Stride+4*lo:]
+			for i := range sums {
+				sums[i] += int(row[i])
+			}
+		}
+		for y := 0; y < height; y++ {
+			out := dst.Pix[y*dst.Stride+4*lo:]
+			add := src.Pix[clampIndex(y+radius+1, height)*src.Stride+4*lo:]
+			sub := src.Pix[clampIndex(y-radius, height)*src.Stride+4*lo:]
+			for i, sum := range sums {
+				out[i] = div.apply(sum)
+				sums[i] = sum + int(add[i]) - int(sub[i])
+			}
+		}
+	})
+}
+
+type divider struct {
+	half int
+	mul  int
+}
+
+func newDivider(size int) divider {
+	return divider{half: size / 2, mul: (1<<32 + size - 1) / size}
+}
+
+func (d divider) apply(sum int) uint8 {
+	return uint8(((sum + d.half) * d.mul) >> 32)
+}
+
+func gaussianBlur(ctx context.Context, src *image.RGBA, sigma float64) (*image.RGBA, error) {
+	if sigma > exactGaussianMaxSigma {
+		blurred := src
+		for _, radius := range gaussianBoxRadii(sigma, gaussianBoxPasses) {
+			var err error
+			if blurred, err = boxBlur(ctx, blurred, radius); err != nil {
+				return nil, err
+			}
+		}
+		return blurred, nil
+	}
+	weights := gaussianWeights(sigma)
+	tmp := image.NewRGBA(src.Rect)
+	if err := convolveRows(ctx, src, tmp, weights); err != nil {
+		return nil, err
+	}
+	dst := image.NewRGBA(src.Rect)
+	if err := convolveColumns(ctx, tmp, dst, weights); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+func gaussianWeights(sigma float64) []float32 {
+	radius := int(math.Ceil(3 * sigma))
+	weights := make([]float32, 2*radius+1)
+	var sum float64
+	raw := make([]float64, len(weights))
+	for i := range raw {
+		d := float64(i - radius)
+		raw[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += raw[i]
+	}
+	for i, w := range raw {
+		weights[i] = float32(w / sum)
+	}
+	return weights
+}
+
+func gaussianBoxRadii(sigma float64, passes int) []int {
+	n := float64(passes)
+	ideal := math.Sqrt(12*sigma*sigma/n + 1)
+	lower := int(math.Floor(ideal))
+	if lower%2 == 0 {
+		lower--
+	}
+	upper := lower + 2
+	fl := float64(lower)
+	m := int(math.Round((12*sigma*sigma - n*fl*fl - 4*n*fl - 3*n) / (-4*fl - 4)))
+	radii := make([]int, passes)
+	for i := range radii {
+		size := upper
+		if i < m {
+			size = lower
+		}
+		radii[i] = (size - 1) / 2
+	}
+	return radii
+}
+
+func convolveRows(ctx context.Context, src, dst *image.RGBA, weights []float32) error {
+	width := src.Rect.Dx()
+	radius := len(weights) / 2
+	return parallelize(ctx, src.Rect.Dy(), func(lo, hi int) {
+		for y := lo; y < hi; y++ {
+			in := src.Pix[y*src.Stride : y*src.Stride+4*width]
+			out := dst.Pix[y*dst.Stride : y*dst.Stride+4*width]
+			for x := 0; x < width; x++ {
+				var sum [4]float32
+				interior := x >= radius && x+radius < width
+				for k, w := range weights {
+					i := x + k - radius
+					if !interior {
+						i = clampIndex(i, width)
+					}
+					px := in[4*i : 4*i+4]
+					sum[0] += w * float32(px[0])
+					sum[1] += w * float32(px[1])
+					sum[2] += w * float32(px[2])
+					sum[3] += w * float32(px[3])
+				}
+				for c, v := range sum {
+					out[4*x+c] = clampByte(v, 255)
+				}
+			}
+		}
+	})
+}
+
+func convolveColumns(ctx context.Context, src, dst *image.RGBA, weights []float32) error {
+	height := src.Rect.Dy()
+	radius := len(weights) / 2
+	return parallelize(ctx, src.Rect.Dx(), func(lo, hi int) {
+		sums := make([]float32, 4*(hi-lo))
+		for y := 0; y < height; y++ {
+			clear(sums)
+			for k, w := range weights {
+				row := src.Pix[clampIndex(y+k-radius, height)*src.Stride+4*lo:]
+				for i := range sums {
+					sums[i] += w * float32(row[i])
+				}
+			}
+			out := dst.Pix[y*dst.Stride+4*lo:]
+			for i, v := range sums {
+				out[i] = clampByte(v, 255)
+			}
+		}
+	})
+}
+
+func convolve(ctx context.Context, src *image.RGBA, matrix []float32, bias float32) (*image.RGBA, error) {
+	size := int(math.Sqrt(float64(len(matrix))))
+	radius := size / 2
+	width, height := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(src.Rect)
+	err := parallelize(ctx, height, func(lo, hi int) {
+		for y := lo; y < hi; y++ {
+			out := dst.Pix[y*dst.Stride : y*dst.Stride+4*width]
+			for x := 0; x < width; x++ {
+				alpha := src.Pix[y*src.Stride+4*x+3]
+				out[4*x+3] = alpha
+				if alpha == 0 {
+					continue
+				}
+				var sum [3]float32
+				for ky := 0; ky < size; ky++ {
+					row := src.Pix[clampIndex(y+ky-radius, height)*src.Stride:]
+					for kx, w := range matrix[ky*size : ky*size+size] {
+						px := row[4*clampIndex(x+kx-radius, width):]
+						sum[0] += w * float32(px[0])
+						sum[1] += w * float32(px[1])
+						sum[2] += w * float32(px[2])
+					}
+				}
+				for c, v := range sum {
+					out[4*x+c] = clampByte(v+bias*float32(alpha)/255, alpha)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+func sobel(ctx context.Context, src *image.RGBA, amount float64) (*image.RGBA, error) {
+	width, height := src.Rect.Dx(), src.Rect.Dy()
+	luma := make([]float32, width*height)
+	err := parallelize(ctx, height, func(lo, hi int) {
+		for y := lo; y < hi; y++ {
+			row := src.Pix[y*src.Stride:]
+			for x := 0; x < width; x++ {
+				px := row[4*x:]
+				luma[y*width+x] = 0.299*float32(px[0]) + 0.587*float32(px[1]) + 0.114*float32(px[2])
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	scale := float32(amount / 4)
+	dst := image.NewRGBA(src.Rect)
+	err = parallelize(ctx, height, func(lo, hi int) {
+		for y := lo; y < hi; y++ {
+			above := luma[clampIndex(y-1, height)*width:]
+			middle := luma[y*width:]
+			below := luma[clampIndex(y+1, height)*width:]
+			out := dst.Pix[y*dst.Stride:]
+			for x := 0; x < width; x++ {
+				left, right := clampIndex(x-1, width), clampIndex(x+1, width)
+				gx := above[right] + 2*middle[right] + below[right] - above[left] - 2*middle[left] - below[left]
+				gy := below[left] + 2*below[x] + below[right] - above[left] - 2*above[x] - above[right]
+				alpha := src.Pix[y*src.Stride+4*x+3]
+				value := clampByte(float32(math.Sqrt(float64(gx*gx+gy*gy)))*scale, alpha)
+				out[4*x], out[4*x+1], out[4*x+2], out[4*x+3] = value, value, value, alpha
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+func unsharpMask(ctx context.Context, src *image.RGBA, sigma, amount float64, threshold int) (*image.RGBA, error) {
+	blurred, err := gaussianBlur(ctx, src, sigma)
+	if err != nil {
+		return nil, err
+	}
+	width := src.Rect.Dx()
+	gain := float32(amount)
+	err = parallelize(ctx, src.Rect.Dy(), func(lo, hi int) {
+		for y := lo; y < hi; y++ {
+			in := src.Pix[y*src.Stride : y*src.Stride+4*width]
+			out := blurred.Pix[y*blurred.Stride : y*blurred.Stride+4*width]
+			for i := 0; i < len(in); i += 4 {
+				alpha := in[i+3]
+				for c := 0; c < 3; c++ {
+					diff := int(in[i+c]) - int(out[i+c])
+					if diff < threshold && -diff < threshold {
+						out[i+c] = in[i+c]
+						continue
+					}
+					out[i+c] = clampByte(float32(in[i+c])+gain*float32(diff), alpha)
+				}
+				out[i+3] = alpha
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return blurred, nil
+}
+
+func clampByte(value float32, limit uint8) uint8 {
+	value += 0.5
+	switch {
+	case value <= 0:
+		return 0
+	case value >= float32(limit):
+		return limit
+	}
+	return uint8(value)
+}
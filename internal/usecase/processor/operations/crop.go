@@ -0,0 +1,62 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+
+	"image-processor/internal/domain"
+)
+
+type Cropper struct{}
+
+func NewCropper() *Cropper {
+	return &Cropper{}
+}
+
+func (c *Cropper) Name() domain.OperationType {
+	return domain.OpCrop
+}
+
+func (c *Cropper) NewParams() domain.Params {
+	return &domain.CropParams{}
+}
+
+func (c *Cropper) PathTemplate() string {
+	return "crop/{image_id}/{x}_{y}_{width}x{height}-{params_hash}.{format}"
+}
+
+func (c *Cropper) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Chainable:         true,
+		PreservesGIF:      true,
+		ChangesDimensions: true,
+	}
+}
+
+func (c *Cropper) Apply(ctx context.Context, img image.Image, params domain.Params) (image.Image, error) {
+	p, err := castParams[*domain.CropParams](params)
+	if err != nil {
+		return nil, err
+	}
+	return cropImage(img, p.X, p.Y, p.Width, p.Height)
+}
+
+func cropImage(img image.Image, x, y, width, height int) (image.Image, error) {
+	if x < 0 || y < 0 {
+		return nil, fmt.Errorf("x and y must be non-negative")
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("width and height must be positive numbers")
+	}
+	bounds := img.Bounds()
+	if x+width > bounds.Dx() || y+height > bounds.Dy() {
+		return nil, fmt.Errorf("crop rectangle %dx%d at (%d,%d) exceeds image bounds %dx%d",
+			width, height, x, y, bounds.Dx(), bounds.Dy())
+	}
+	src := image.Rect(x, y, x+width, y+height).Add(bounds.Min)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), img, src.Min, draw.Src)
+	return dst, nil
+}
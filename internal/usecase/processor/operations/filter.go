@@ -0,0 +1,102 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"image"
+
+	"image-processor/internal/domain"
+)
+
+var embossMatrix = []float32{
+	-2, -1, 0,
+	-1, 0, 1,
+	0, 1, 2,
+}
+
+type Filter struct{}
+
+func NewFilter() *Filter {
+	return &Filter{}
+}
+
+func (f *Filter) Name() domain.OperationType {
+	return domain.OpFilter
+}
+
+func (f *Filter) NewParams() domain.Params {
+	return &domain.FilterParams{
+		Filter: domain.FilterGaussianBlur,
+		Radius: domain.DefaultFilterRadius,
+		Amount: domain.DefaultFilterAmount,
+	}
+}
+
+func (f *Filter) PathTemplate() string {
+	return "filtered/{image_id}/{filter}-{params_hash}.{format}"
+}
+
+func (f *Filter) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Chainable:         true,
+		PreservesGIF:      true,
+		ChangesDimensions: false,
+	}
+}
+
+func (f *Filter) Apply(ctx context.Context, img image.Image, params domain.Params) (image.Image, error) {
+	p, err := castParams[*domain.FilterParams](params)
+	if err != nil {
+		return nil, err
+	}
+	src := cloneRGBA(img)
+	var filtered *image.RGBA
+	switch p.Filter {
+	case domain.FilterGaussianBlur:
+		filtered, err = gaussianBlur(ctx, src, p.Radius)
+	case domain.FilterBoxBlur:
+		filtered, err = boxBlur(ctx, src, int(p.Radius))
+	case domain.FilterSharpen:
+		a := float32(p.Amount)
+		filtered, err = convolve(ctx, src, []float32{
+			0, -a, 0,
+			-a, 1 + 4*a, -a,
+			0, -a, 0,
+		}, 0)
+	case domain.FilterUnsharp:
+		filtered, err = unsharpMask(ctx, src, p.Radius, p.Amount, p.Threshold)
+	case domain.FilterEdge:
+		filtered, err = sobel(ctx, src, p.Amount)
+	case domain.FilterEmboss:
+		matrix := make([]float32, len(embossMatrix))
+		for i, w := range embossMatrix {
+			matrix[i] = float32(p.Amount) * w
+		}
+		matrix[len(matrix)/2] = 1
+		filtered, err = convolve(ctx, src, matrix, 0)
+	case domain.FilterCustom:
+		filtered, err = convolve(ctx, src, normalizeMatrix(p.Matrix, p.Divisor), float32(p.Bias))
+	default:
+		return nil, fmt.Errorf("%w: unknown filter %q", ErrInvalidOperation, p.Filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return filtered, nil
+}
+
+func normalizeMatrix(weights []float64, divisor float64) []float32 {
+	if divisor == 0 {
+		for _, w := range weights {
+			divisor += w
+		}
+		if divisor == 0 {
+			divisor = 1
+		}
+	}
+	matrix := make([]float32, len(weights))
+	for i, w := range weights {
+		matrix[i] = float32(w / divisor)
+	}
+	return matrix
+}
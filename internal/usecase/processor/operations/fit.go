@@ -0,0 +1,108 @@
+package operations
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"image-processor/internal/domain"
+)
+
+type fitOptions struct {
+	fit        domain.ResizeFit
+	gravity    domain.Gravity
+	background string
+	noUpscale  bool
+	kernel     domain.ResampleKernel
+	report     *Report
+}
+
+func fitImage(img image.Image, width, height int, opts fitOptions) (image.Image, error) {
+	bounds := img.Bounds()
+	origWidth, origHeight := bounds.Dx(), bounds.Dy()
+	if width <= 0 && height <= 0 {
+		return nil, fmt.Errorf("width or height must be a positive number")
+	}
+	if width <= 0 {
+		width = scaleDimension(origWidth, float64(height)/float64(origHeight))
+	}
+	if height <= 0 {
+		height = scaleDimension(origHeight, float64(width)/float64(origWidth))
+	}
+	widthRatio := float64(width) / float64(origWidth)
+	heightRatio := float64(height) / float64(origHeight)
+	anchorX, anchorY := opts.gravity.Anchor()
+	switch opts.fit {
+	case domain.FitInside, domain.FitContain:
+		ratio := capRatio(math.Min(widthRatio, heightRatio), opts.noUpscale)
+		scaled := resizeImage(img, scaleDimension(origWidth, ratio), scaleDimension(origHeight, ratio), opts.kernel)
+		if opts.fit == domain.FitInside {
+			return scaled, nil
+		}
+		background := opts.background
+		if background == "" {
+			background = domain.DefaultFitBackground
+		}
+		bg, err := parseColor(background, 1)
+		if err != nil {
+			return nil, fmt.Errorf("invalid background color %q: %w", background, err)
+		}
+		canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.NRGBA{R: bg.R, G: bg.G, B: bg.B, A: bg.A}), image.Point{}, draw.Src)
+		size := scaled.Bounds().Size()
+		offset := image.Pt(anchorOffset(width-size.X, anchorX), anchorOffset(height-size.Y, anchorY))
+		draw.Draw(canvas, image.Rectangle{Min: offset, Max: offset.Add(size)}, scaled, scaled.Bounds().Min, draw.Over)
+		return canvas, nil
+	case domain.FitOutside:
+		ratio := capRatio(math.Max(widthRatio, heightRatio), opts.noUpscale)
+		return resizeImage(img, scaleDimension(origWidth, ratio), scaleDimension(origHeight, ratio), opts.kernel), nil
+	case domain.FitCover:
+		ratio := capRatio(math.Max(widthRatio, heightRatio), opts.noUpscale)
+		cropWidth := int(math.Min(float64(origWidth), math.Round(float64(width)/ratio)))
+		cropHeight := int(math.Min(float64(origHeight), math.Round(float64(height)/ratio)))
+		crop := image.Rect(0, 0, cropWidth, cropHeight).Add(bounds.Min).Add(image.Pt(
+			anchorOffset(origWidth-cropWidth, anchorX),
+			anchorOffset(origHeight-cropHeight, anchorY),
+		))
+		if opts.gravity == domain.GravitySmart {
+			if recorded, ok := opts.report.Crop(); ok && recorded.Size() == crop.Size() && recorded.In(bounds) {
+				crop = recorded
+			} else {
+				crop = smartCrop(img, cropWidth, cropHeight)
+			}
+		}
+		if crop != bounds {
+			opts.report.recordCrop(crop)
+		}
+		dst := image.NewRGBA(image.Rect(0, 0, min(width, scaleDimension(cropWidth, ratio)), min(height, scaleDimension(cropHeight, ratio))))
+		scaleImage(dst, img, crop, opts.kernel)
+		return dst, nil
+	}
+	if opts.noUpscale {
+		width, height = min(width, origWidth), min(height, origHeight)
+	}
+	return resizeImage(img, width, height, opts.kernel), nil
+}
+
+func capRatio(ratio float64, noUpscale bool) float64 {
+	if noUpscale && ratio > 1 {
+		return 1
+	}
+	return ratio
+}
+
+func scaleDimension(size int, ratio float64) int {
+	return int(math.Max(1, math.Round(float64(size)*ratio)))
+}
+
+func anchorOffset(space int, anchor float64) int {
+	return int(math.Round(float64(space) * anchor))
+}
+
+func resizeImage(img image.Image, width, height int, kernel domain.ResampleKernel) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	scaleImage(dst, img, img.Bounds(), kernel)
+	return dst
+}
@@ -0,0 +1,121 @@
+package operations
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"image-processor/internal/domain"
+)
+
+func solidImage(rect image.Rectangle, c color.Color) *image.RGBA {
+	img := image.NewRGBA(rect)
+	draw.Draw(img, rect, image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func TestFitImageGeometry(t *testing.T) {
+	src := solidImage(image.Rect(0, 0, 400, 200), color.RGBA{R: 255, A: 255})
+	offset := solidImage(image.Rect(10, 10, 410, 210), color.RGBA{R: 255, A: 255})
+	tests := []struct {
+		name     string
+		src      image.Image
+		width    int
+		height   int
+		opts     fitOptions
+		wantSize image.Point
+		wantCrop image.Rectangle
+	}{
+		{"fill", src, 100, 100, fitOptions{fit: domain.FitFill}, image.Pt(100, 100), image.Rectangle{}},
+		{"fill no upscale", src, 800, 800, fitOptions{fit: domain.FitFill, noUpscale: true}, image.Pt(400, 200), image.Rectangle{}},
+		{"inside", src, 100, 100, fitOptions{fit: domain.FitInside}, image.Pt(100, 50), image.Rectangle{}},
+		{"inside width only", src, 200, 0, fitOptions{fit: domain.FitInside}, image.Pt(200, 100), image.Rectangle{}},
+		{"inside height only", src, 0, 50, fitOptions{fit: domain.FitInside}, image.Pt(100, 50), image.Rectangle{}},
+		{"inside upscale", src, 800, 800, fitOptions{fit: domain.FitInside}, image.Pt(800, 400), image.Rectangle{}},
+		{"inside no upscale", src, 800, 800, fitOptions{fit: domain.FitInside, noUpscale: true}, image.Pt(400, 200), image.Rectangle{}},
+		{"contain", src, 100, 100, fitOptions{fit: domain.FitContain}, image.Pt(100, 100), image.Rectangle{}},
+		{"outside", src, 100, 100, fitOptions{fit: domain.FitOutside}, image.Pt(200, 100), image.Rectangle{}},
+		{"outside no upscale", src, 800, 800, fitOptions{fit: domain.FitOutside, noUpscale: true}, image.Pt(400, 200), image.Rectangle{}},
+		{"cover center", src, 100, 100, fitOptions{fit: domain.FitCover}, image.Pt(100, 100), image.Rect(100, 0, 300, 200)},
+		{"cover west", src, 100, 100, fitOptions{fit: domain.FitCover, gravity: domain.GravityWest}, image.Pt(100, 100), image.Rect(0, 0, 200, 200)},
+		{"cover east", src, 100, 100, fitOptions{fit: domain.FitCover, gravity: domain.GravityEast}, image.Pt(100, 100), image.Rect(200, 0, 400, 200)},
+		{"cover north", src, 200, 50, fitOptions{fit: domain.FitCover, gravity: domain.GravityNorth}, image.Pt(200, 50), image.Rect(0, 0, 400, 100)},
+		{"cover south", src, 200, 50, fitOptions{fit: domain.FitCover, gravity: domain.GravitySouth}, image.Pt(200, 50), image.Rect(0, 100, 400, 200)},
+		{"cover no upscale", src, 800, 800, fitOptions{fit: domain.FitCover, noUpscale: true}, image.Pt(400, 200), image.Rectangle{}},
+		{"cover offset bounds", offset, 100, 100, fitOptions{fit: domain.FitCover, gravity: domain.GravityWest}, image.Pt(100, 100), image.Rect(10, 10, 210, 210)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.report = &Report{}
+			got, err := fitImage(tt.src, tt.width, tt.height, tt.opts)
+			if err != nil {
+				t.Fatalf("fitImage() error = %v", err)
+			}
+			if size := got.Bounds().Size(); size != tt.wantSize {
+				t.Errorf("fitImage() size = %v, want %v", size, tt.wantSize)
+			}
+			crop, ok := tt.opts.report.Crop()
+			if tt.wantCrop.Empty() {
+				if ok {
+					t.Errorf("fitImage() recorded crop %v, want none", crop)
+				}
+				return
+			}
+			if !ok || crop != tt.wantCrop {
+				t.Errorf("fitImage() crop = %v (%v), want %v", crop, ok, tt.wantCrop)
+			}
+		})
+	}
+}
+
+func TestFitImageContainPlacement(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	src := solidImage(image.Rect(0, 0, 400, 200), red)
+	tests := []struct {
+		name       string
+		gravity    domain.Gravity
+		background string
+		image      image.Point
+		padding    image.Point
+		wantPad    color.RGBA
+	}{
+		{"center", domain.GravityCenter, "", image.Pt(50, 50), image.Pt(50, 10), color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		{"north", domain.GravityNorth, "0,0,255", image.Pt(50, 10), image.Pt(50, 90), color.RGBA{B: 255, A: 255}},
+		{"south", domain.GravitySouth, "0,255,0,255", image.Pt(50, 90), image.Pt(50, 10), color.RGBA{G: 255, A: 255}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fitImage(src, 100, 100, fitOptions{fit: domain.FitContain, gravity: tt.gravity, background: tt.background})
+			if err != nil {
+				t.Fatalf("fitImage() error = %v", err)
+			}
+			if c := color.RGBAModel.Convert(got.At(tt.image.X, tt.image.Y)); c != red {
+				t.Errorf("pixel %v = %v, want image colour %v", tt.image, c, red)
+			}
+			if c := color.RGBAModel.Convert(got.At(tt.padding.X, tt.padding.Y)); c != tt.wantPad {
+				t.Errorf("pixel %v = %v, want background %v", tt.padding, c, tt.wantPad)
+			}
+		})
+	}
+}
+
+func TestFitImageErrors(t *testing.T) {
+	src := solidImage(image.Rect(0, 0, 10, 10), color.Black)
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		opts   fitOptions
+	}{
+		{"no dimensions", 0, 0, fitOptions{}},
+		{"bad background", 20, 20, fitOptions{fit: domain.FitContain, background: "white"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fitImage(src, tt.width, tt.height, tt.opts); err == nil {
+				t.Error("fitImage() error = nil, want an error")
+			}
+		})
+	}
+}
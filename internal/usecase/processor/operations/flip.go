@@ -0,0 +1,74 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"image"
+
+	"image-processor/internal/domain"
+)
+
+type Flipper struct{}
+
+func NewFlipper() *Flipper {
+	return &Flipper{}
+}
+
+func (f *Flipper) Name() domain.OperationType {
+	return domain.OpFlip
+}
+
+func (f *Flipper) NewParams() domain.Params {
+	return &domain.FlipParams{Direction: domain.FlipHorizontal}
+}
+
+func (f *Flipper) PathTemplate() string {
+	return "flip/{image_id}/{direction}-{params_hash}.{format}"
+}
+
+func (f *Flipper) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Chainable:         true,
+		PreservesGIF:      true,
+		ChangesDimensions: false,
+	}
+}
+
+func (f *Flipper) Apply(ctx context.Context, img image.Image, params domain.Params) (image.Image, error) {
+	p, err := castParams[*domain.FlipParams](params)
+	if err != nil {
+		return nil, err
+	}
+	return flipImage(img, p.Direction)
+}
+
+func flipImage(img image.Image, direction domain.FlipDirection) (image.Image, error) {
+	var horizontal, vertical bool
+	switch direction {
+	case domain.FlipHorizontal:
+		horizontal = true
+	case domain.FlipVertical:
+		vertical = true
+	case domain.FlipBoth:
+		horizontal, vertical = true, true
+	default:
+		return nil, fmt.Errorf("invalid flip direction: %s", direction)
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		srcY := y
+		if vertical {
+			srcY = height - 1 - y
+		}
+		for x := 0; x < width; x++ {
+			srcX := x
+			if horizontal {
+				srcX = width - 1 - x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+srcX, bounds.Min.Y+srcY))
+		}
+	}
+	return dst, nil
+}
@@ -0,0 +1,55 @@
+package operations
+
+import (
+	"context"
+	"image"
+	"image/color"
+
+	"image-processor/internal/domain"
+)
+
+type Grayscaler struct{}
+
+func NewGrayscaler() *Grayscaler {
+	return &Grayscaler{}
+}
+
+func (g *Grayscaler) Name() domain.OperationType {
+	return domain.OpGrayscale
+}
+
+func (g *Grayscaler) NewParams() domain.Params {
+	return &domain.GrayscaleParams{}
+}
+
+func (g *Grayscaler) PathTemplate() string {
+	return "grayscale/{image_id}/{params_hash}.{format}"
+}
+
+func (g *Grayscaler) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Chainable:         true,
+		PreservesGIF:      true,
+		ChangesDimensions: false,
+	}
+}
+
+func (g *Grayscaler) Apply(ctx context.Context, img image.Image, params domain.Params) (image.Image, error) {
+	if _, err := castParams[*domain.GrayscaleParams](params); err != nil {
+		return nil, err
+	}
+	return grayscaleImage(img), nil
+}
+
+func grayscaleImage(img image.Image) image.Image {
+	bounds := img.Bounds()
+	dst := image.NewRGBA64(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			lum := uint16((19595*r + 38470*g + 7471*b + 1<<15) >> 16)
+			dst.SetRGBA64(x-bounds.Min.X, y-bounds.Min.Y, color.RGBA64{R: lum, G: lum, B: lum, A: uint16(a)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,36 @@
+package operations
+
+import (
+	"image"
+
+	"image-processor/internal/domain"
+)
+
+func Orient(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return mustFlip(img, domain.FlipHorizontal)
+	case 3:
+		return rotateRight(img, 2)
+	case 4:
+		return mustFlip(img, domain.FlipVertical)
+	case 5:
+		return mustFlip(rotateRight(img, 1), domain.FlipHorizontal)
+	case 6:
+		return rotateRight(img, 1)
+	case 7:
+		return mustFlip(rotateRight(img, 3), domain.FlipHorizontal)
+	case 8:
+		return rotateRight(img, 3)
+	default:
+		return img
+	}
+}
+
+func mustFlip(img image.Image, direction domain.FlipDirection) image.Image {
+	flipped, err := flipImage(img, direction)
+	if err != nil {
+		return img
+	}
+	return flipped
+}
@@ -0,0 +1,57 @@
+package operations
+
+import (
+	"context"
+	"image"
+	"image/draw"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const chunksPerWorker = 4
+
+func parallelize(ctx context.Context, n int, fn func(lo, hi int)) error {
+	if n <= 0 {
+		return ctx.Err()
+	}
+	workers := min(runtime.GOMAXPROCS(0), n)
+	chunk := max(1, (n+workers*chunksPerWorker-1)/(workers*chunksPerWorker))
+	var (
+		next atomic.Int64
+		wg   sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				hi := int(next.Add(int64(chunk)))
+				lo := hi - chunk
+				if lo >= n {
+					return
+				}
+				fn(lo, min(hi, n))
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func cloneRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	return dst
+}
+
+func clampIndex(i, n int) int {
+	switch {
+	case i < 0:
+		return 0
+	case i >= n:
+		return n - 1
+	}
+	return i
+}
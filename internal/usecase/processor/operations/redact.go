@@ -0,0 +1,168 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"sort"
+
+	"image-processor/internal/domain"
+)
+
+type Redactor struct{}
+
+func NewRedactor() *Redactor {
+	return &Redactor{}
+}
+
+func (r *Redactor) Name() domain.OperationType {
+	return domain.OpRedact
+}
+
+func (r *Redactor) NewParams() domain.Params {
+	return &domain.RedactParams{
+		Mode:      domain.RedactPixelate,
+		BlockSize: domain.DefaultRedactBlockSize,
+		Radius:    domain.DefaultRedactRadius,
+		Color:     domain.DefaultRedactColor,
+	}
+}
+
+func (r *Redactor) PathTemplate() string {
+	return "redacted/{image_id}/{mode}-{params_hash}.{format}"
+}
+
+func (r *Redactor) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Chainable:         true,
+		PreservesGIF:      true,
+		ChangesDimensions: false,
+	}
+}
+
+func (r *Redactor) Apply(ctx context.Context, img image.Image, params domain.Params) (image.Image, error) {
+	p, err := castParams[*domain.RedactParams](params)
+	if err != nil {
+		return nil, err
+	}
+	dst := cloneRGBA(img)
+	for _, region := range p.Regions {
+		area, mask := redactArea(region, dst.Rect)
+		if area.Empty() {
+			continue
+		}
+		var patch image.Image
+		switch p.Mode {
+		case domain.RedactPixelate:
+			patch, err = pixelate(ctx, dst.SubImage(area).(*image.RGBA), p.BlockSize)
+		case domain.RedactBlur:
+			patch, err = gaussianBlur(ctx, dst.SubImage(area).(*image.RGBA), p.Radius)
+		case domain.RedactFill:
+			patch = image.NewUniform(watermarkColor(p.Color, domain.DefaultRedactColor))
+		default:
+			return nil, fmt.Errorf("%w: unknown redact mode %q", ErrInvalidOperation, p.Mode)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if mask == nil {
+			draw.Draw(dst, area, patch, area.Min, draw.Src)
+			continue
+		}
+		layer := image.NewRGBA(area)
+		draw.Draw(layer, area, patch, area.Min, draw.Src)
+		for y := area.Min.Y; y < area.Max.Y; y++ {
+			for x := area.Min.X; x < area.Max.X; x++ {
+				if mask.AlphaAt(x, y).A != 0 {
+					i := layer.PixOffset(x, y)
+					copy(dst.Pix[dst.PixOffset(x, y):], layer.Pix[i:i+4])
+				}
+			}
+		}
+	}
+	return dst, nil
+}
+
+func redactArea(region domain.RedactRegion, bounds image.Rectangle) (image.Rectangle, *image.Alpha) {
+	if region.Points == nil {
+		return image.Rect(region.X, region.Y, region.X+region.Width, region.Y+region.Height).Intersect(bounds), nil
+	}
+	var box image.Rectangle
+	for i, point := range region.Points {
+		cell := image.Rect(point[0], point[1], point[0]+1, point[1]+1)
+		if i == 0 {
+			box = cell
+		} else {
+			box = box.Union(cell)
+		}
+	}
+	area := box.Intersect(bounds)
+	if area.Empty() {
+		return area, nil
+	}
+	mask := image.NewAlpha(area)
+	crossings := make([]float64, 0, len(region.Points))
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		center := float64(y) + 0.5
+		crossings = crossings[:0]
+		for i, from := range region.Points {
+			to := region.Points[(i+1)%len(region.Points)]
+			y0, y1 := float64(from[1]), float64(to[1])
+			if (y0 <= center) == (y1 <= center) {
+				continue
+			}
+			x0, x1 := float64(from[0]), float64(to[0])
+			crossings = append(crossings, x0+(center-y0)*(x1-x0)/(y1-y0))
+		}
+		sort.Float64s(crossings)
+		row := mask.Pix[(y-area.Min.Y)*mask.Stride:]
+		for i := 0; i+1 < len(crossings); i += 2 {
+			lo := max(area.Min.X, int(math.Ceil(crossings[i]-0.5)))
+			hi := min(area.Max.X, int(math.Ceil(crossings[i+1]-0.5)))
+			for x := lo; x < hi; x++ {
+				row[x-area.Min.X] = 0xff
+			}
+		}
+	}
+	return area, mask
+}
+
+func pixelate(ctx context.Context, src *image.RGBA, blockSize int) (*image.RGBA, error) {
+	width, height := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(src.Rect)
+	err := parallelize(ctx, (height+blockSize-1)/blockSize, func(lo, hi int) {
+		for by := lo; by < hi; by++ {
+			top, bottom := by*blockSize, min(height, (by+1)*blockSize)
+			for left := 0; left < width; left += blockSize {
+				right := min(width, left+blockSize)
+				var sums [4]int
+				for y := top; y < bottom; y++ {
+					row := src.Pix[y*src.Stride+4*left : y*src.Stride+4*right]
+					for i := 0; i < len(row); i += 4 {
+						sums[0] += int(row[i])
+						sums[1] += int(row[i+1])
+						sums[2] += int(row[i+2])
+						sums[3] += int(row[i+3])
+					}
+				}
+				count := (bottom - top) * (right - left)
+				var average [4]uint8
+				for c, sum := range sums {
+					average[c] = uint8((sum + count/2) / count)
+				}
+				for y := top; y < bottom; y++ {
+					row := dst.Pix[y*dst.Stride+4*left : y*dst.Stride+4*right]
+					for i := 0; i < len(row); i += 4 {
+						copy(row[i:i+4], average[:])
+					}
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
@@ -0,0 +1,178 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"image-processor/internal/domain"
+)
+
+var (
+	ErrDuplicateOperation = errors.New("operation already registered")
+	ErrInvalidOperation   = errors.New("invalid operation")
+	ErrInvalidParams      = errors.New("invalid params type")
+)
+
+type Operation interface {
+	Name() domain.OperationType
+	NewParams() domain.Params
+	Apply(ctx context.Context, img image.Image, params domain.Params) (image.Image, error)
+	PathTemplate() string
+	Capabilities() domain.Capabilities
+}
+
+type Registry struct {
+	mu  sync.RWMutex
+	ops map[domain.OperationType]Operation
+}
+
+var defaultRegistry = NewRegistry()
+
+func init() {
+	for _, op := range []Operation{
+		NewResizer(),
+		NewThumbnailer(),
+		NewWatermarker(),
+		NewCropper(),
+		NewRotator(),
+		NewFlipper(),
+		NewGrayscaler(),
+		NewAdjuster(),
+		NewFilter(),
+		NewRedactor(),
+		NewResponsive(),
+	} {
+		MustRegister(op)
+	}
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		ops: make(map[domain.OperationType]Operation),
+	}
+}
+
+func Default() *Registry {
+	return defaultRegistry
+}
+
+func Register(op Operation) error {
+	return defaultRegistry.Register(op)
+}
+
+func MustRegister(op Operation) {
+	if err := Register(op); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Registry) Register(op Operation) error {
+	if op == nil || op.Name() == "" {
+		return fmt.Errorf("%w: operation must have a name", ErrInvalidOperation)
+	}
+	if op.NewParams() == nil {
+		return fmt.Errorf("%w: %s has no params factory", ErrInvalidOperation, op.Name())
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.ops[op.Name()]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateOperation, op.Name())
+	}
+	r.ops[op.Name()] = op
+	domain.RegisterParams(op.Name(), op.NewParams)
+	return nil
+}
+
+func (r *Registry) Lookup(name domain.OperationType) (Operation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	op, ok := r.ops[name]
+	return op, ok
+}
+
+func (r *Registry) Describe(name domain.OperationType) (domain.OperationDescriptor, bool) {
+	op, ok := r.Lookup(name)
+	if !ok {
+		return domain.OperationDescriptor{}, false
+	}
+	return describe(op), true
+}
+
+func (r *Registry) DescribeAll() []domain.OperationDescriptor {
+	r.mu.RLock()
+	descriptors := make([]domain.OperationDescriptor, 0, len(r.ops))
+	for _, op := range r.ops {
+		descriptors = append(descriptors, describe(op))
+	}
+	r.mu.RUnlock()
+	sort.Slice(descriptors, func(i, j int) bool {
+		return descriptors[i].Name < descriptors[j].Name
+	})
+	return descriptors
+}
+
+func describe(op Operation) domain.OperationDescriptor {
+	return domain.OperationDescriptor{
+		Name:         op.Name(),
+		Params:       describeParams(op.NewParams()),
+		PathTemplate: op.PathTemplate(),
+		Capabilities: op.Capabilities(),
+	}
+}
+
+func describeParams(params domain.Params) []domain.ParamDescriptor {
+	value := reflect.Indirect(reflect.ValueOf(params))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	var descriptors []domain.ParamDescriptor
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		descriptors = append(descriptors, domain.ParamDescriptor{
+			Name:    name,
+			Type:    paramType(field.Type),
+			Default: value.Field(i).Interface(),
+		})
+	}
+	return descriptors
+}
+
+func paramType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func castParams[T domain.Params](params domain.Params) (T, error) {
+	typed, ok := params.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%w: %T", ErrInvalidParams, params)
+	}
+	return typed, nil
+}
@@ -0,0 +1,47 @@
+package operations
+
+import (
+	"context"
+	"image"
+	"sync"
+)
+
+type Report struct {
+	mu   sync.Mutex
+	crop *image.Rectangle
+}
+
+type reportContextKey struct{}
+
+func WithReport(ctx context.Context) (context.Context, *Report) {
+	report := &Report{}
+	return context.WithValue(ctx, reportContextKey{}, report), report
+}
+
+func reportFrom(ctx context.Context) *Report {
+	report, _ := ctx.Value(reportContextKey{}).(*Report)
+	return report
+}
+
+func (r *Report) Crop() (image.Rectangle, bool) {
+	if r == nil {
+		return image.Rectangle{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.crop == nil {
+		return image.Rectangle{}, false
+	}
+	return *r.crop, true
+}
+
+func (r *Report) recordCrop(rect image.Rectangle) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.crop == nil {
+		r.crop = &rect
+	}
+}
@@ -0,0 +1,81 @@
+package operations
+
+import (
+	"context"
+	"image"
+	"math"
+
+	"image-processor/internal/domain"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+const multiStepFactor = 3
+
+var lanczos = &xdraw.Kernel{
+	Support: 3,
+	At: func(t float64) float64 {
+		if t == 0 {
+			return 1
+		}
+		if t >= 3 {
+			return 0
+		}
+		x := math.Pi * t
+		return 3 * math.Sin(x) * math.Sin(x/3) / (x * x)
+	},
+}
+
+type kernelContextKey struct{}
+
+func WithDefaultKernel(ctx context.Context, kernel domain.ResampleKernel) context.Context {
+	return context.WithValue(ctx, kernelContextKey{}, kernel)
+}
+
+func resolveKernel(ctx context.Context, kernel domain.ResampleKernel) domain.ResampleKernel {
+	if kernel != "" {
+		return kernel
+	}
+	if fallback, ok := ctx.Value(kernelContextKey{}).(domain.ResampleKernel); ok && fallback != "" {
+		return fallback
+	}
+	return domain.KernelBilinear
+}
+
+func interpolator(kernel domain.ResampleKernel) xdraw.Interpolator {
+	switch kernel {
+	case domain.KernelNearest:
+		return xdraw.NearestNeighbor
+	case domain.KernelApproxBilinear:
+		return xdraw.ApproxBiLinear
+	case domain.KernelCatmullRom:
+		return xdraw.CatmullRom
+	case domain.KernelLanczos:
+		return lanczos
+	default:
+		return xdraw.BiLinear
+	}
+}
+
+func scaleImage(dst *image.RGBA, src image.Image, srcRect image.Rectangle, kernel domain.ResampleKernel) {
+	if kernel != domain.KernelNearest && kernel != domain.KernelApproxBilinear {
+		for srcRect.Dx() >= multiStepFactor*dst.Bounds().Dx() && srcRect.Dy() >= multiStepFactor*dst.Bounds().Dy() {
+			half := image.NewRGBA(image.Rect(0, 0, (srcRect.Dx()+1)/2, (srcRect.Dy()+1)/2))
+			xdraw.ApproxBiLinear.Scale(half, half.Bounds(), src, srcRect, xdraw.Src, nil)
+			src, srcRect = half, half.Bounds()
+		}
+	}
+	interpolator(kernel).Scale(dst, dst.Bounds(), src, srcRect, xdraw.Over, nil)
+}
+
+func Downscale(img image.Image, maxSize int) *image.RGBA {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if longest := max(width, height); longest > maxSize {
+		width = max(1, int(math.Round(float64(width)*float64(maxSize)/float64(longest))))
+		height = max(1, int(math.Round(float64(height)*float64(maxSize)/float64(longest))))
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	scaleImage(dst, img, bounds, domain.KernelBilinear)
+	return dst
+}
@@ -0,0 +1,130 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"sort"
+	"strconv"
+	"strings"
+
+	"image-processor/internal/domain"
+)
+
+type Output struct {
+	Name       string
+	Descriptor string
+	Operation  domain.OperationType
+	Params     domain.Params
+}
+
+type MultiOutputOperation interface {
+	Operation
+	Outputs(bounds image.Rectangle, params domain.Params) ([]Output, error)
+}
+
+type Responsive struct{}
+
+func NewResponsive() *Responsive {
+	return &Responsive{}
+}
+
+func (r *Responsive) Name() domain.OperationType {
+	return domain.OpResponsive
+}
+
+func (r *Responsive) NewParams() domain.Params {
+	return &domain.ResponsiveParams{
+		Widths: append([]int(nil), domain.DefaultResponsiveWidths...),
+		Sizes:  domain.DefaultResponsiveSizes,
+	}
+}
+
+func (r *Responsive) PathTemplate() string {
+	return "responsive/{image_id}/{name}-{params_hash}.{format}"
+}
+
+func (r *Responsive) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		PreservesGIF:      true,
+		ChangesDimensions: true,
+		MultiOutput:       true,
+	}
+}
+
+func (r *Responsive) Apply(ctx context.Context, img image.Image, params domain.Params) (image.Image, error) {
+	outputs, err := r.Outputs(img.Bounds(), params)
+	if err != nil {
+		return nil, err
+	}
+	largest := outputs[0]
+	for _, output := range outputs[1:] {
+		if output.Params.(*domain.ResizeParams).Width > largest.Params.(*domain.ResizeParams).Width {
+			largest = output
+		}
+	}
+	return NewResizer().Apply(ctx, img, largest.Params)
+}
+
+func (r *Responsive) Outputs(bounds image.Rectangle, params domain.Params) ([]Output, error) {
+	p, err := castParams[*domain.ResponsiveParams](params)
+	if err != nil {
+		return nil, err
+	}
+	origWidth := bounds.Dx()
+	if origWidth <= 0 || bounds.Dy() <= 0 {
+		return nil, fmt.Errorf("image has no pixels")
+	}
+	var outputs []Output
+	widths := uniqueWidths(p.Widths, origWidth)
+	if len(p.Widths) > 0 && len(widths) == 0 {
+		widths = []int{origWidth}
+	}
+	for _, width := range widths {
+		descriptor := strconv.Itoa(width) + "w"
+		outputs = append(outputs, Output{
+			Name:       descriptor,
+			Descriptor: descriptor,
+			Operation:  domain.OpResize,
+			Params:     &domain.ResizeParams{Width: width, Fit: domain.FitInside},
+		})
+	}
+	densities := append([]float64(nil), p.Densities...)
+	sort.Float64s(densities)
+	seen := make(map[string]bool)
+	for _, density := range densities {
+		width := int(float64(p.BaseWidth)*density + 0.5)
+		if width > origWidth && len(seen) > 0 {
+			continue
+		}
+		descriptor := strconv.FormatFloat(density, 'f', -1, 64) + "x"
+		if seen[descriptor] {
+			continue
+		}
+		seen[descriptor] = true
+		outputs = append(outputs, Output{
+			Name:       strings.ReplaceAll(descriptor, ".", "_"),
+			Descriptor: descriptor,
+			Operation:  domain.OpResize,
+			Params:     &domain.ResizeParams{Width: min(width, origWidth), Fit: domain.FitInside},
+		})
+	}
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("no responsive outputs requested")
+	}
+	return outputs, nil
+}
+
+func uniqueWidths(requested []int, maxWidth int) []int {
+	seen := make(map[int]bool)
+	var widths []int
+	for _, width := range requested {
+		if width <= 0 || width > maxWidth || seen[width] {
+			continue
+		}
+		seen[width] = true
+		widths = append(widths, width)
+	}
+	sort.Ints(widths)
+	return widths
+}
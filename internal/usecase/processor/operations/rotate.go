@@ -0,0 +1,116 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"image-processor/internal/domain"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+)
+
+type Rotator struct{}
+
+func NewRotator() *Rotator {
+	return &Rotator{}
+}
+
+func (r *Rotator) Name() domain.OperationType {
+	return domain.OpRotate
+}
+
+func (r *Rotator) NewParams() domain.Params {
+	return &domain.RotateParams{Background: domain.DefaultRotateBackground}
+}
+
+func (r *Rotator) PathTemplate() string {
+	return "rotate/{image_id}/{angle}-{params_hash}.{format}"
+}
+
+func (r *Rotator) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Chainable:         true,
+		PreservesGIF:      true,
+		ChangesDimensions: true,
+	}
+}
+
+func (r *Rotator) Apply(ctx context.Context, img image.Image, params domain.Params) (image.Image, error) {
+	p, err := castParams[*domain.RotateParams](params)
+	if err != nil {
+		return nil, err
+	}
+	background := p.Background
+	bg, err := parseColor(background, 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid background color %q: %w", background, err)
+	}
+	return rotateImage(img, p.Angle, color.NRGBA{R: bg.R, G: bg.G, B: bg.B, A: bg.A}), nil
+}
+
+func rotateImage(img image.Image, angle float64, background color.Color) image.Image {
+	angle = math.Mod(angle, 360)
+	if angle < 0 {
+		angle += 360
+	}
+	switch angle {
+	case 0:
+		return rotateRight(img, 0)
+	case 90:
+		return rotateRight(img, 1)
+	case 180:
+		return rotateRight(img, 2)
+	case 270:
+		return rotateRight(img, 3)
+	}
+	bounds := img.Bounds()
+	width, height := float64(bounds.Dx()), float64(bounds.Dy())
+	rad := angle * math.Pi / 180
+	sin, cos := math.Sin(rad), math.Cos(rad)
+	newWidth := int(math.Ceil(math.Abs(width*cos) + math.Abs(height*sin)))
+	newHeight := int(math.Ceil(math.Abs(width*sin) + math.Abs(height*cos)))
+	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	srcCX := float64(bounds.Min.X) + width/2
+	srcCY := float64(bounds.Min.Y) + height/2
+	dstCX := float64(newWidth) / 2
+	dstCY := float64(newHeight) / 2
+	s2d := f64.Aff3{
+		cos, -sin, dstCX - (cos*srcCX - sin*srcCY),
+		sin, cos, dstCY - (sin*srcCX + cos*srcCY),
+	}
+	xdraw.BiLinear.Transform(dst, s2d, img, bounds, xdraw.Over, nil)
+	return dst
+}
+
+func rotateRight(img image.Image, quarterTurns int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstWidth, dstHeight := width, height
+	if quarterTurns%2 == 1 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch quarterTurns {
+			case 1:
+				dx, dy = height-1-y, x
+			case 2:
+				dx, dy = width-1-x, height-1-y
+			case 3:
+				dx, dy = y, width-1-x
+			default:
+				dx, dy = x, y
+			}
+			dst.Set(dx, dy, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
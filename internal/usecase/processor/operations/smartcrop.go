@@ -0,0 +1,117 @@
+package operations
+
+import (
+	"image"
+	"math"
+
+	"image-processor/internal/domain"
+)
+
+const (
+	smartCropAnalysisSize = 256
+	smartCropPositions    = 64
+	smartCropEdgeWeight   = 1.0
+	smartCropSatWeight    = 0.3
+	smartCropSkinWeight   = 0.6
+)
+
+var skinTone = [3]float64{0.78, 0.57, 0.44}
+
+func smartCrop(img image.Image, cropWidth, cropHeight int) image.Rectangle {
+	bounds := img.Bounds()
+	origWidth, origHeight := bounds.Dx(), bounds.Dy()
+	scale := math.Min(1, float64(smartCropAnalysisSize)/float64(max(origWidth, origHeight)))
+	width, height := scaleDimension(origWidth, scale), scaleDimension(origHeight, scale)
+	sample := image.NewRGBA(image.Rect(0, 0, width, height))
+	scaleImage(sample, img, bounds, domain.KernelBilinear)
+	integral := saliencyIntegral(sample)
+	windowWidth := min(width, scaleDimension(cropWidth, scale))
+	windowHeight := min(height, scaleDimension(cropHeight, scale))
+	stepX := max(1, (width-windowWidth)/smartCropPositions)
+	stepY := max(1, (height-windowHeight)/smartCropPositions)
+	centerX, centerY := float64(width-windowWidth)/2, float64(height-windowHeight)/2
+	best := image.Point{}
+	bestScore, bestDistance := math.Inf(-1), math.Inf(1)
+	for y := 0; y <= height-windowHeight; y += stepY {
+		for x := 0; x <= width-windowWidth; x += stepX {
+			insetX, insetY := windowWidth/4, windowHeight/4
+			score := integral.sum(x, y, x+windowWidth, y+windowHeight) +
+				integral.sum(x+insetX, y+insetY, x+windowWidth-insetX, y+windowHeight-insetY)
+			distance := math.Hypot(float64(x)-centerX, float64(y)-centerY)
+			if score > bestScore+1e-9 || (math.Abs(score-bestScore) <= 1e-9 && distance < bestDistance) {
+				best, bestScore, bestDistance = image.Pt(x, y), score, distance
+			}
+		}
+	}
+	x := min(origWidth-cropWidth, int(math.Round(float64(best.X)/scale)))
+	y := min(origHeight-cropHeight, int(math.Round(float64(best.Y)/scale)))
+	return image.Rect(x, y, x+cropWidth, y+cropHeight).Add(bounds.Min)
+}
+
+type integralImage struct {
+	width  int
+	values []float64
+}
+
+func (ii integralImage) sum(x0, y0, x1, y1 int) float64 {
+	stride := ii.width + 1
+	return ii.values[y1*stride+x1] - ii.values[y0*stride+x1] - ii.values[y1*stride+x0] + ii.values[y0*stride+x0]
+}
+
+func saliencyIntegral(img *image.RGBA) integralImage {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	luma := make([]float64, width*height)
+	scores := make([]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			offset := img.PixOffset(x, y)
+			r := float64(img.Pix[offset]) / 255
+			g := float64(img.Pix[offset+1]) / 255
+			b := float64(img.Pix[offset+2]) / 255
+			luma[y*width+x] = 0.299*r + 0.587*g + 0.114*b
+			scores[y*width+x] = smartCropSatWeight*saturation(r, g, b) + smartCropSkinWeight*skinScore(r, g, b)
+		}
+	}
+	at := func(x, y int) float64 {
+		return luma[min(max(y, 0), height-1)*width+min(max(x, 0), width-1)]
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			gx := at(x+1, y-1) + 2*at(x+1, y) + at(x+1, y+1) - at(x-1, y-1) - 2*at(x-1, y) - at(x-1, y+1)
+			gy := at(x-1, y+1) + 2*at(x, y+1) + at(x+1, y+1) - at(x-1, y-1) - 2*at(x, y-1) - at(x+1, y-1)
+			scores[y*width+x] += smartCropEdgeWeight * math.Min(1, math.Hypot(gx, gy)/4)
+		}
+	}
+	stride := width + 1
+	values := make([]float64, stride*(height+1))
+	for y := 0; y < height; y++ {
+		var row float64
+		for x := 0; x < width; x++ {
+			row += scores[y*width+x]
+			values[(y+1)*stride+x+1] = values[y*stride+x+1] + row
+		}
+	}
+	return integralImage{width: width, values: values}
+}
+
+func saturation(r, g, b float64) float64 {
+	high := math.Max(r, math.Max(g, b))
+	low := math.Min(r, math.Min(g, b))
+	if high == 0 {
+		return 0
+	}
+	return (high - low) / high
+}
+
+func skinScore(r, g, b float64) float64 {
+	length := math.Sqrt(r*r + g*g + b*b)
+	if length == 0 {
+		return 0
+	}
+	distance := math.Sqrt(math.Pow(r/length-skinTone[0], 2) + math.Pow(g/length-skinTone[1], 2) + math.Pow(b/length-skinTone[2], 2))
+	luminance := 0.299*r + 0.587*g + 0.114*b
+	if luminance < 0.2 || luminance > 0.95 {
+		return 0
+	}
+	return math.Max(0, 1-distance/0.15)
+}
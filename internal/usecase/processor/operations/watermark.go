@@ -1,34 +1,30 @@
 package operations
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
-	"image/jpeg"
-	"image/png"
-	"io"
 	"math"
 	"strconv"
 	"strings"
 
 	"image-processor/internal/domain"
 
-	"github.com/golang/freetype"
-	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
 	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
 	"golang.org/x/image/math/fixed"
 )
 
 type Watermarker struct {
-	font *truetype.Font
+	font *sfnt.Font
 }
 
 func NewWatermarker() *Watermarker {
-	fontBytes := goregular.TTF
-	f, err := truetype.Parse(fontBytes)
+	f, err := opentype.Parse(goregular.TTF)
 	if err != nil {
 		return &Watermarker{}
 	}
@@ -37,123 +33,243 @@ func NewWatermarker() *Watermarker {
 	}
 }
 
-func (w *Watermarker) Process(ctx context.Context, img image.Image, format string, params map[string]interface{}) (io.Reader, string, error) {
-	text, ok := params["text"].(string)
-	if !ok || text == "" {
-		text = domain.DefaultWatermarkText
+func (w *Watermarker) Name() domain.OperationType {
+	return domain.OpWatermark
+}
+
+func (w *Watermarker) NewParams() domain.Params {
+	return &domain.WatermarkParams{
+		Text:        domain.DefaultWatermarkText,
+		Opacity:     domain.DefaultWatermarkOpacity,
+		Position:    domain.WatermarkBottomRight,
+		FontSize:    domain.DefaultWatermarkFontSize,
+		FontColor:   domain.DefaultWatermarkFontColor,
+		Scale:       domain.DefaultWatermarkScale,
+		Margin:      domain.DefaultWatermarkMargin,
+		StrokeColor: domain.DefaultWatermarkStrokeColor,
+		ShadowColor: domain.DefaultWatermarkShadowColor,
+		LineSpacing: domain.DefaultWatermarkLineSpacing,
 	}
-	opacity, ok := params["opacity"].(float64)
-	if !ok || opacity <= 0 {
-		opacity = domain.DefaultWatermarkOpacity
+}
+
+func (w *Watermarker) PathTemplate() string {
+	return "watermarked/{image_id}/{params_hash}.{format}"
+}
+
+func (w *Watermarker) Capabilities() domain.Capabilities {
+	return domain.Capabilities{
+		Chainable:         true,
+		PreservesGIF:      true,
+		ChangesDimensions: false,
 	}
-	position, ok := params["position"].(string)
-	if !ok {
-		position = string(domain.WatermarkBottomRight)
+}
+
+func (w *Watermarker) Apply(ctx context.Context, img image.Image, params domain.Params) (image.Image, error) {
+	p, err := castParams[*domain.WatermarkParams](params)
+	if err != nil {
+		return nil, err
+	}
+	var mark image.Image
+	if p.WatermarkID != "" {
+		mark, err = w.logoMark(ctx, img.Bounds(), p)
+	} else {
+		mark, err = w.textMark(ctx, p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to add watermark: %w", err)
 	}
-	fontSize, ok := params["font_size"].(float64)
-	if !ok || fontSize <= 0 {
-		fontSize = 36
+	if p.Angle != 0 {
+		mark = rotateImage(mark, p.Angle, color.Transparent)
 	}
-	fontColor, ok := params["font_color"].(string)
+	return drawWatermark(ctx, img, mark, p)
+}
+
+func (w *Watermarker) logoMark(ctx context.Context, bounds image.Rectangle, p *domain.WatermarkParams) (image.Image, error) {
+	loader, ok := assetLoaderFrom(ctx)
 	if !ok {
-		fontColor = "255,255,255"
+		return nil, fmt.Errorf("watermark assets are not available")
 	}
-	watermarked, err := w.addTextWatermark(img, text, position, opacity, fontSize, fontColor)
+	logo, err := loader.LoadAsset(ctx, p.WatermarkID)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to add watermark: %w", err)
-	}
-	buf := new(bytes.Buffer)
-	switch strings.ToLower(format) {
-	case "jpg", "jpeg":
-		err = jpeg.Encode(buf, watermarked, &jpeg.Options{Quality: domain.DefaultJPEGQuality})
-		format = "jpeg"
-	case "png":
-		err = png.Encode(buf, watermarked)
-		format = "png"
-	case "gif":
-		err = jpeg.Encode(buf, watermarked, &jpeg.Options{Quality: domain.DefaultJPEGQuality})
-		format = "jpeg"
-	default:
-		err = jpeg.Encode(buf, watermarked, &jpeg.Options{Quality: domain.DefaultJPEGQuality})
-		format = "jpeg"
+		return nil, fmt.Errorf("failed to load watermark %s: %w", p.WatermarkID, err)
+	}
+	logoBounds := logo.Bounds()
+	if logoBounds.Empty() {
+		return nil, fmt.Errorf("watermark %s is empty", p.WatermarkID)
 	}
+	scale := p.Scale
+	if scale <= 0 {
+		scale = domain.DefaultWatermarkScale
+	}
+	width := scaleDimension(bounds.Dx(), scale)
+	height := scaleDimension(logoBounds.Dy(), float64(width)/float64(logoBounds.Dx()))
+	mark := image.NewRGBA(image.Rect(0, 0, width, height))
+	scaleImage(mark, logo, logoBounds, resolveKernel(ctx, ""))
+	return mark, nil
+}
+
+func (w *Watermarker) textMark(ctx context.Context, p *domain.WatermarkParams) (image.Image, error) {
+	face, err := w.fontFace(ctx, p)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to encode watermarked image: %w", err)
+		return nil, err
+	}
+	defer face.Close()
+	lines := strings.Split(strings.ReplaceAll(p.Text, "\r\n", "\n"), "\n")
+	lineSpacing := p.LineSpacing
+	if lineSpacing <= 0 {
+		lineSpacing = domain.DefaultWatermarkLineSpacing
+	}
+	metrics := face.Metrics()
+	ascent, descent := metrics.Ascent.Ceil(), metrics.Descent.Ceil()
+	lineHeight := int(math.Ceil(p.FontSize * lineSpacing))
+	widths := make([]int, len(lines))
+	blockWidth := 0
+	for i, line := range lines {
+		widths[i] = font.MeasureString(face, line).Ceil()
+		blockWidth = max(blockWidth, widths[i])
+	}
+	if blockWidth == 0 {
+		return nil, fmt.Errorf("watermark text is empty")
+	}
+	pad := p.StrokeWidth
+	blockHeight := ascent + descent + (len(lines)-1)*lineHeight
+	glyphs := image.NewAlpha(image.Rect(0, 0, blockWidth+2*pad, blockHeight+2*pad))
+	drawer := font.Drawer{Dst: glyphs, Src: image.Opaque, Face: face}
+	for i, line := range lines {
+		drawer.Dot = fixed.P(pad+alignOffset(p.Position, p.Tile, blockWidth, widths[i]), pad+ascent+i*lineHeight)
+		drawer.DrawString(line)
+	}
+	outline := glyphs
+	if p.StrokeWidth > 0 {
+		outline = dilate(glyphs, p.StrokeWidth)
+	}
+	shadowOffset := image.Pt(p.ShadowOffset, p.ShadowOffset)
+	mark := image.NewRGBA(image.Rectangle{Max: glyphs.Bounds().Max.Add(shadowOffset)})
+	if p.ShadowOffset > 0 {
+		shadow := image.NewUniform(watermarkColor(p.ShadowColor, domain.DefaultWatermarkShadowColor))
+		draw.DrawMask(mark, outline.Bounds().Add(shadowOffset), shadow, image.Point{}, outline, image.Point{}, draw.Over)
 	}
-	return buf, format, nil
+	if p.StrokeWidth > 0 {
+		stroke := image.NewUniform(watermarkColor(p.StrokeColor, domain.DefaultWatermarkStrokeColor))
+		draw.DrawMask(mark, outline.Bounds(), stroke, image.Point{}, outline, image.Point{}, draw.Over)
+	}
+	fill := image.NewUniform(watermarkColor(p.FontColor, domain.DefaultWatermarkFontColor))
+	draw.DrawMask(mark, glyphs.Bounds(), fill, image.Point{}, glyphs, image.Point{}, draw.Over)
+	return mark, nil
 }
 
-func (w *Watermarker) addTextWatermark(img image.Image, text, position string, opacity, fontSize float64, fontColorStr string) (image.Image, error) {
-	if w.font == nil {
+func (w *Watermarker) fontFace(ctx context.Context, p *domain.WatermarkParams) (font.Face, error) {
+	face := w.font
+	if p.FontID != "" {
+		loader, ok := assetLoaderFrom(ctx)
+		if !ok {
+			return nil, fmt.Errorf("watermark fonts are not available")
+		}
+		loaded, err := loader.LoadFont(ctx, p.FontID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load font %s: %w", p.FontID, err)
+		}
+		face = loaded
+	}
+	if face == nil {
 		return nil, fmt.Errorf("font not loaded")
 	}
+	return opentype.NewFace(face, &opentype.FaceOptions{
+		Size:    p.FontSize,
+		DPI:     72,
+		Hinting: font.HintingNone,
+	})
+}
+
+func drawWatermark(ctx context.Context, img, mark image.Image, p *domain.WatermarkParams) (image.Image, error) {
 	bounds := img.Bounds()
+	markBounds := mark.Bounds()
 	result := image.NewRGBA(bounds)
-	draw.Draw(result, bounds, img, image.Point{}, draw.Src)
-	col, err := parseColor(fontColorStr, opacity)
-	if err != nil {
-		fmt.Printf("Color parse error: %v, using black\n", err)
-		col = color.RGBA{0, 0, 0, uint8(255 * opacity)}
-	}
-	c := freetype.NewContext()
-	c.SetDPI(72)
-	c.SetFont(w.font)
-	c.SetFontSize(fontSize)
-	c.SetClip(result.Bounds())
-	c.SetDst(result)
-	c.SetSrc(image.NewUniform(col))
-	face := truetype.NewFace(w.font, &truetype.Options{
-		Size: fontSize,
-		DPI:  72,
-	})
-	var textWidth fixed.Int26_6
-	for _, x := range text {
-		awidth, ok := face.GlyphAdvance(x)
-		if ok {
-			textWidth += awidth
+	draw.Draw(result, bounds, img, bounds.Min, draw.Src)
+	mask := image.NewUniform(color.Alpha{A: uint8(math.Round(255 * p.Opacity))})
+	if !p.Tile {
+		origin := watermarkOrigin(bounds, markBounds.Size(), p.Position, p.Margin)
+		draw.DrawMask(result, markBounds.Sub(markBounds.Min).Add(origin), mark, markBounds.Min, mask, image.Point{}, draw.Over)
+		return result, nil
+	}
+	stepX, stepY := markBounds.Dx()+p.Spacing, markBounds.Dy()+p.Spacing
+	for row, y := 0, bounds.Min.Y+p.Margin; y < bounds.Max.Y; row, y = row+1, y+stepY {
+		x := bounds.Min.X + p.Margin
+		if row%2 == 1 {
+			x -= stepX / 2
 		}
+		for ; x < bounds.Max.X; x += stepX {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			draw.DrawMask(result, markBounds.Sub(markBounds.Min).Add(image.Pt(x, y)), mark, markBounds.Min, mask, image.Point{}, draw.Over)
+		}
+	}
+	return result, nil
+}
+
+func watermarkOrigin(bounds image.Rectangle, size image.Point, position domain.WatermarkPosition, margin int) image.Point {
+	x := bounds.Max.X - size.X - margin
+	y := bounds.Max.Y - size.Y - margin
+	switch position {
+	case domain.WatermarkTopLeft, domain.WatermarkBottomLeft:
+		x = bounds.Min.X + margin
+	case domain.WatermarkTopCenter, domain.WatermarkBottomCenter, domain.WatermarkCenter:
+		x = bounds.Min.X + (bounds.Dx()-size.X)/2
 	}
-	textHeight := fixed.Int26_6(fontSize * 64 * 1.2)
-	widthPx := int(textWidth.Ceil())
-	heightPx := int(textHeight.Ceil())
-	fmt.Printf("Text '%s': width=%dpx, height=%dpx\n", text, widthPx, heightPx)
-	fmt.Printf("Image size: %dx%d\n", bounds.Dx(), bounds.Dy())
-	margin := 20
-	var pt fixed.Point26_6
-	switch domain.WatermarkPosition(position) {
-	case domain.WatermarkTopLeft:
-		pt = freetype.Pt(margin, margin+heightPx)
-		fmt.Println("Position: top-left")
-	case domain.WatermarkTopRight:
-		pt = freetype.Pt(bounds.Dx()-widthPx-margin, margin+heightPx)
-		fmt.Println("Position: top-right")
-	case domain.WatermarkTopCenter:
-		pt = freetype.Pt((bounds.Dx()-widthPx)/2, margin+heightPx)
-		fmt.Println("Position: top-center")
-	case domain.WatermarkBottomLeft:
-		pt = freetype.Pt(margin, bounds.Dy()-margin)
-		fmt.Println("Position: bottom-left")
-	case domain.WatermarkBottomRight:
-		pt = freetype.Pt(bounds.Dx()-widthPx-margin, bounds.Dy()-margin)
-		fmt.Println("Position: bottom-right")
-	case domain.WatermarkBottomCenter:
-		pt = freetype.Pt((bounds.Dx()-widthPx)/2, bounds.Dy()-margin)
-		fmt.Println("Position: bottom-center")
+	switch position {
+	case domain.WatermarkTopLeft, domain.WatermarkTopRight, domain.WatermarkTopCenter:
+		y = bounds.Min.Y + margin
 	case domain.WatermarkCenter:
-		pt = freetype.Pt((bounds.Dx()-widthPx)/2, (bounds.Dy()+heightPx)/2)
-		fmt.Println("Position: center")
+		y = bounds.Min.Y + (bounds.Dy()-size.Y)/2
+	}
+	return image.Pt(x, y)
+}
+
+func alignOffset(position domain.WatermarkPosition, tile bool, blockWidth, lineWidth int) int {
+	if tile {
+		return (blockWidth - lineWidth) / 2
+	}
+	switch position {
+	case domain.WatermarkTopLeft, domain.WatermarkBottomLeft:
+		return 0
+	case domain.WatermarkTopCenter, domain.WatermarkBottomCenter, domain.WatermarkCenter:
+		return (blockWidth - lineWidth) / 2
 	default:
-		pt = freetype.Pt(bounds.Dx()-widthPx-margin, bounds.Dy()-margin)
-		fmt.Println("Position: default (bottom-right)")
+		return blockWidth - lineWidth
 	}
-	fmt.Printf("Drawing at: x=%d, y=%d\n", pt.X.Ceil(), pt.Y.Ceil())
-	c.SetFontSize(fontSize)
-	_, err = c.DrawString(text, pt)
+}
+
+func dilate(src *image.Alpha, radius int) *image.Alpha {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dst := image.NewAlpha(bounds)
+	for dy := -radius; dy <= radius; dy++ {
+		span := int(math.Sqrt(float64(radius*radius - dy*dy)))
+		for y := max(0, -dy); y < height && y+dy < height; y++ {
+			srcRow := src.Pix[(y+dy)*src.Stride : (y+dy)*src.Stride+width]
+			dstRow := dst.Pix[y*dst.Stride : y*dst.Stride+width]
+			for x, a := range srcRow {
+				if a == 0 {
+					continue
+				}
+				for tx := max(0, x-span); tx <= min(width-1, x+span); tx++ {
+					if dstRow[tx] < a {
+						dstRow[tx] = a
+					}
+				}
+			}
+		}
+	}
+	return dst
+}
+
+func watermarkColor(value, fallback string) color.Color {
+	c, err := parseColor(value, 1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to draw watermark text: %w", err)
+		c, _ = parseColor(fallback, 1)
 	}
-	fmt.Println("Watermark drawn successfully")
-	return result, nil
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}
 }
 
 func parseColor(colorStr string, opacity float64) (color.RGBA, error) {
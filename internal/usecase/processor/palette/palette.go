@@ -0,0 +1,131 @@
+package palette
+
+import (
+	"image"
+	"math"
+	"sort"
+
+	"image-processor/internal/domain"
+	"image-processor/internal/usecase/processor/encoder"
+)
+
+const (
+	kMeansIterations = 10
+	minOpaqueAlpha   = 128
+	weightPrecision  = 10000
+)
+
+type sample struct {
+	rgb [3]uint8
+	lab [3]float64
+}
+
+type cluster struct {
+	lab   [3]float64
+	sum   [3]float64
+	rgb   [3]int
+	count int
+}
+
+func Extract(img *image.RGBA, size int) ([]domain.PaletteColor, string) {
+	samples, average := collect(img)
+	if len(samples) == 0 {
+		return nil, average
+	}
+	var clusters []cluster
+	for _, seed := range encoder.MedianCut(img, size) {
+		r, g, b, a := seed.RGBA()
+		if a == 0 {
+			continue
+		}
+		clusters = append(clusters, cluster{lab: domain.LabFromRGB(uint8(r>>8), uint8(g>>8), uint8(b>>8))})
+	}
+	assignments := make([]int, len(samples))
+	for iteration := 0; iteration < kMeansIterations; iteration++ {
+		changed := false
+		for i := range clusters {
+			clusters[i].sum, clusters[i].rgb, clusters[i].count = [3]float64{}, [3]int{}, 0
+		}
+		for i, s := range samples {
+			nearest := 0
+			best := math.Inf(1)
+			for k, c := range clusters {
+				if distance := domain.LabDistance(s.lab, c.lab); distance < best {
+					nearest, best = k, distance
+				}
+			}
+			if iteration == 0 || assignments[i] != nearest {
+				changed = true
+			}
+			assignments[i] = nearest
+			c := &clusters[nearest]
+			c.count++
+			for ch := 0; ch < 3; ch++ {
+				c.sum[ch] += s.lab[ch]
+				c.rgb[ch] += int(s.rgb[ch])
+			}
+		}
+		for i := range clusters {
+			if c := &clusters[i]; c.count > 0 {
+				for ch := 0; ch < 3; ch++ {
+					c.lab[ch] = c.sum[ch] / float64(c.count)
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	colors := make([]domain.PaletteColor, 0, len(clusters))
+	for _, c := range clusters {
+		if c.count == 0 {
+			continue
+		}
+		r := uint8((c.rgb[0] + c.count/2) / c.count)
+		g := uint8((c.rgb[1] + c.count/2) / c.count)
+		b := uint8((c.rgb[2] + c.count/2) / c.count)
+		colors = append(colors, domain.PaletteColor{
+			Color:  domain.HexColor(r, g, b),
+			Weight: math.Round(float64(c.count)/float64(len(samples))*weightPrecision) / weightPrecision,
+			Lab:    domain.LabFromRGB(r, g, b),
+		})
+	}
+	sort.SliceStable(colors, func(i, j int) bool {
+		return colors[i].Weight > colors[j].Weight
+	})
+	return colors, average
+}
+
+func collect(img *image.RGBA) ([]sample, string) {
+	var (
+		samples []sample
+		sum     [3]int
+		alpha   int
+	)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(img.Rect.Min.X, y):img.PixOffset(img.Rect.Max.X, y)]
+		for i := 0; i < len(row); i += 4 {
+			a := int(row[i+3])
+			for ch := 0; ch < 3; ch++ {
+				sum[ch] += int(row[i+ch])
+			}
+			alpha += a
+			if a < minOpaqueAlpha {
+				continue
+			}
+			var rgb [3]uint8
+			for ch := range rgb {
+				rgb[ch] = uint8(min(255, (int(row[i+ch])*255+a/2)/a))
+			}
+			samples = append(samples, sample{rgb: rgb, lab: domain.LabFromRGB(rgb[0], rgb[1], rgb[2])})
+		}
+	}
+	if alpha == 0 {
+		return samples, ""
+	}
+	var average [3]uint8
+	for ch := range average {
+		average[ch] = uint8(min(255, (sum[ch]*255+alpha/2)/alpha))
+	}
+	return samples, domain.HexColor(average[0], average[1], average[2])
+}
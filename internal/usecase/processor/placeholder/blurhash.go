@@ -0,0 +1,102 @@
+package placeholder
+
+import (
+	"image"
+	"math"
+	"strings"
+)
+
+const (
+	blurHashLongComponents  = 4
+	blurHashShortComponents = 3
+	base83Alphabet          = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+)
+
+func BlurHash(img *image.RGBA) string {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	if width == 0 || height == 0 {
+		return ""
+	}
+	nx, ny := blurHashLongComponents, blurHashShortComponents
+	if height > width {
+		nx, ny = ny, nx
+	}
+	pixels := flatten(img)
+	factors := make([][3]float64, 0, nx*ny)
+	for cy := 0; cy < ny; cy++ {
+		for cx := 0; cx < nx; cx++ {
+			normalisation := 2.0
+			if cx == 0 && cy == 0 {
+				normalisation = 1
+			}
+			var factor [3]float64
+			for y := 0; y < height; y++ {
+				fy := math.Cos(math.Pi * float64(cy) * float64(y) / float64(height))
+				for x := 0; x < width; x++ {
+					basis := fy * math.Cos(math.Pi*float64(cx)*float64(x)/float64(width))
+					pixel := pixels[y*width+x]
+					for c := range factor {
+						factor[c] += basis * srgbToLinear(pixel[c])
+					}
+				}
+			}
+			for c := range factor {
+				factor[c] *= normalisation / float64(width*height)
+			}
+			factors = append(factors, factor)
+		}
+	}
+	var hash strings.Builder
+	writeBase83(&hash, (nx-1)+(ny-1)*9, 1)
+	maximum := 1.0
+	if len(factors) > 1 {
+		actual := 0.0
+		for _, factor := range factors[1:] {
+			for _, value := range factor {
+				actual = math.Max(actual, math.Abs(value))
+			}
+		}
+		quantised := int(math.Max(0, math.Min(82, math.Floor(actual*166-0.5))))
+		maximum = float64(quantised+1) / 166
+		writeBase83(&hash, quantised, 1)
+	} else {
+		writeBase83(&hash, 0, 1)
+	}
+	dc := factors[0]
+	writeBase83(&hash, linearToSRGB(dc[0])<<16|linearToSRGB(dc[1])<<8|linearToSRGB(dc[2]), 4)
+	for _, factor := range factors[1:] {
+		value := 0
+		for _, component := range factor {
+			quantised := int(math.Max(0, math.Min(18, math.Floor(signPow(component/maximum, 0.5)*9+9.5))))
+			value = value*19 + quantised
+		}
+		writeBase83(&hash, value, 2)
+	}
+	return hash.String()
+}
+
+func writeBase83(b *strings.Builder, value, length int) {
+	for i := 1; i <= length; i++ {
+		digit := value / int(math.Pow(83, float64(length-i))) % 83
+		b.WriteByte(base83Alphabet[digit])
+	}
+}
+
+func srgbToLinear(value float64) float64 {
+	if value <= 0.04045 {
+		return value / 12.92
+	}
+	return math.Pow((value+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(value float64) int {
+	value = math.Max(0, math.Min(1, value))
+	if value <= 0.0031308 {
+		return int(value*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(value, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
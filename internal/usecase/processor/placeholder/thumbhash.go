@@ -0,0 +1,153 @@
+package placeholder
+
+import (
+	"encoding/base64"
+	"image"
+	"math"
+)
+
+const MaxSize = 100
+
+const (
+	thumbHashLuminanceLimit = 7
+	thumbHashAlphaLimit     = 5
+)
+
+func ThumbHash(img *image.RGBA) string {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	if width == 0 || height == 0 || width > MaxSize || height > MaxSize {
+		return ""
+	}
+	_, coverage := averageColor(img)
+	hasAlpha := coverage < float64(width*height)
+	limit := thumbHashLuminanceLimit
+	if hasAlpha {
+		limit = thumbHashAlphaLimit
+	}
+	longest := float64(max(width, height))
+	lx := max(1, int(roundHalfUp(float64(limit*width)/longest)))
+	ly := max(1, int(roundHalfUp(float64(limit*height)/longest)))
+	pixels := flatten(img)
+	l, p, q, a := make([]float64, len(pixels)), make([]float64, len(pixels)), make([]float64, len(pixels)), make([]float64, len(pixels))
+	for i, pixel := range pixels {
+		r, g, b := pixel[0], pixel[1], pixel[2]
+		l[i] = (r + g + b) / 3
+		p[i] = (r+g)/2 - b
+		q[i] = r - g
+		a[i] = float64(img.Pix[img.PixOffset(i%width+img.Rect.Min.X, i/width+img.Rect.Min.Y)+3]) / 255
+	}
+	lChannel := encodeThumbHashChannel(l, width, height, max(3, lx), max(3, ly))
+	pChannel := encodeThumbHashChannel(p, width, height, 3, 3)
+	qChannel := encodeThumbHashChannel(q, width, height, 3, 3)
+	landscape := width > height
+	header24 := int(roundHalfUp(63*lChannel.dc)) |
+		int(roundHalfUp(31.5+31.5*pChannel.dc))<<6 |
+		int(roundHalfUp(31.5+31.5*qChannel.dc))<<12 |
+		int(roundHalfUp(31*lChannel.scale))<<18
+	header16 := int(roundHalfUp(63*pChannel.scale))<<3 | int(roundHalfUp(63*qChannel.scale))<<9
+	if hasAlpha {
+		header24 |= 1 << 23
+	}
+	if landscape {
+		header16 |= ly | 1<<15
+	} else {
+		header16 |= lx
+	}
+	hash := []byte{byte(header24), byte(header24 >> 8), byte(header24 >> 16), byte(header16), byte(header16 >> 8)}
+	channels := []thumbHashChannel{lChannel, pChannel, qChannel}
+	if hasAlpha {
+		aChannel := encodeThumbHashChannel(a, width, height, 5, 5)
+		hash = append(hash, byte(int(roundHalfUp(15*aChannel.dc))|int(roundHalfUp(15*aChannel.scale))<<4))
+		channels = append(channels, aChannel)
+	}
+	start, index := len(hash), 0
+	for _, channel := range channels {
+		for _, factor := range channel.ac {
+			if start+index>>1 == len(hash) {
+				hash = append(hash, 0)
+			}
+			hash[start+index>>1] |= byte(int(roundHalfUp(15*factor)) << ((index & 1) << 2))
+			index++
+		}
+	}
+	return base64.StdEncoding.EncodeToString(hash)
+}
+
+type thumbHashChannel struct {
+	dc    float64
+	ac    []float64
+	scale float64
+}
+
+func encodeThumbHashChannel(values []float64, width, height, nx, ny int) thumbHashChannel {
+	var channel thumbHashChannel
+	fx := make([]float64, width)
+	for cy := 0; cy < ny; cy++ {
+		for cx := 0; cx*ny < nx*(ny-cy); cx++ {
+			for x := range fx {
+				fx[x] = math.Cos(math.Pi / float64(width) * float64(cx) * (float64(x) + 0.5))
+			}
+			f := 0.0
+			for y := 0; y < height; y++ {
+				fy := math.Cos(math.Pi / float64(height) * float64(cy) * (float64(y) + 0.5))
+				for x, weight := range fx {
+					f += values[x+y*width] * weight * fy
+				}
+			}
+			f /= float64(width * height)
+			if cx == 0 && cy == 0 {
+				channel.dc = f
+				continue
+			}
+			channel.ac = append(channel.ac, f)
+			channel.scale = math.Max(channel.scale, math.Abs(f))
+		}
+	}
+	if channel.scale > 0 {
+		for i, f := range channel.ac {
+			channel.ac[i] = 0.5 + 0.5/channel.scale*f
+		}
+	}
+	return channel
+}
+
+func averageColor(img *image.RGBA) ([3]float64, float64) {
+	var sum [3]float64
+	coverage := 0.0
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(img.Rect.Min.X, y):img.PixOffset(img.Rect.Max.X, y)]
+		for i := 0; i < len(row); i += 4 {
+			for c := range sum {
+				sum[c] += float64(row[i+c]) * (1.0 / 255)
+			}
+			coverage += float64(row[i+3]) / 255
+		}
+	}
+	if coverage > 0 {
+		for c := range sum {
+			sum[c] /= coverage
+		}
+	}
+	return sum, coverage
+}
+
+func flatten(img *image.RGBA) [][3]float64 {
+	average, _ := averageColor(img)
+	pixels := make([][3]float64, 0, img.Rect.Dx()*img.Rect.Dy())
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(img.Rect.Min.X, y):img.PixOffset(img.Rect.Max.X, y)]
+		for i := 0; i < len(row); i += 4 {
+			alpha := float64(row[i+3]) / 255
+			var pixel [3]float64
+			for c := range pixel {
+				pixel[c] = average[c]*(1-alpha) + float64(row[i+c])*(1.0/255)
+			}
+			pixels = append(pixels, pixel)
+		}
+	}
+	return pixels
+}
+
+func roundHalfUp(value float64) float64 {
+	return math.Floor(value + 0.5)
+}
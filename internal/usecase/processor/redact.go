@@ -0,0 +1,67 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"image-processor/internal/domain"
+)
+
+func (p *ImageProcessor) applyOriginalRedactions(ctx context.Context, task *domain.ProcessingTask, frames *frameSet, report *domain.MetadataReport) (*frameSet, error) {
+	op, ok := p.registry.Lookup(domain.OpRedact)
+	if !ok {
+		return frames, nil
+	}
+	current := frames
+	for _, operation := range task.Operations {
+		params, ok := operation.Parameters.(*domain.RedactParams)
+		if !ok || !params.ReplaceOriginal {
+			continue
+		}
+		if domain.IsRedactedOriginalPath(task.OriginalPath) {
+			p.logger.Info().Str("image_id", task.ImageID).Str("path", task.OriginalPath).Msg("Original image already replaced with redacted version")
+			frames.redacted = true
+			report.OriginalRedacted = true
+			return frames, nil
+		}
+		next, err := current.apply(ctx, op, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to redact original: %w", err)
+		}
+		current = next
+	}
+	if current != frames {
+		current.redacted = true
+	}
+	return current, nil
+}
+
+func (p *ImageProcessor) replaceOriginal(ctx context.Context, task *domain.ProcessingTask, frames *frameSet, report *domain.MetadataReport) error {
+	encoded, err := p.encode(frames, p.outputOptions(domain.OutputOptions{}, string(frames.format), true))
+	if err != nil {
+		return fmt.Errorf("failed to encode redacted original: %w", err)
+	}
+	previousPath := task.OriginalPath
+	redactedPath := domain.RedactedOriginalPath(previousPath, encoded.Format)
+	if err := p.fileRepo.SaveProcessed(ctx, redactedPath, bytes.NewReader(encoded.Data), int64(len(encoded.Data)), encoded.MimeType); err != nil {
+		return fmt.Errorf("failed to save redacted original: %w", err)
+	}
+	if err := p.originals.UpdateOriginal(ctx, task.ImageID, redactedPath, int64(len(encoded.Data)), encoded.MimeType); err != nil {
+		return fmt.Errorf("failed to persist redacted original: %w", err)
+	}
+	task.OriginalPath = redactedPath
+	if err := p.fileRepo.DeleteObject(ctx, previousPath); err != nil {
+		p.logger.Warn().Err(err).Str("image_id", task.ImageID).Str("path", previousPath).Msg("Failed to delete unredacted original")
+	}
+	report.OriginalRedacted = true
+	report.OriginalSize = int64(len(encoded.Data))
+	report.OriginalMimeType = encoded.MimeType
+	p.logger.Info().
+		Str("image_id", task.ImageID).
+		Str("path", redactedPath).
+		Str("format", string(encoded.Format)).
+		Int("size", len(encoded.Data)).
+		Msg("Original image replaced with redacted version")
+	return nil
+}
@@ -0,0 +1,27 @@
+package watermark
+
+import (
+	"context"
+	"image"
+	"io"
+
+	"image-processor/internal/domain"
+)
+
+type watermarkRepository interface {
+	Create(ctx context.Context, asset *domain.WatermarkAsset) error
+	GetByID(ctx context.Context, id string) (*domain.WatermarkAsset, error)
+	List(ctx context.Context) ([]domain.WatermarkAsset, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type fileRepository interface {
+	GetObject(ctx context.Context, path string) (io.ReadCloser, error)
+	SaveProcessed(ctx context.Context, path string, data io.Reader, size int64, contentType string) error
+	DeleteObject(ctx context.Context, path string) error
+}
+
+type imageDecoder interface {
+	Decode(data []byte) (image.Image, domain.ImageFormat, error)
+	DetectFormat(header []byte) (domain.ImageFormat, string, bool)
+}
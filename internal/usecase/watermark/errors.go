@@ -0,0 +1,11 @@
+package watermark
+
+import "errors"
+
+var (
+	ErrWatermarkNotFound = errors.New("watermark not found")
+	ErrInvalidFileFormat = errors.New("invalid file format")
+	ErrFileTooLarge      = errors.New("file too large")
+	ErrStorageError      = errors.New("storage error")
+	ErrDatabaseError     = errors.New("database error")
+)
@@ -0,0 +1,120 @@
+package watermark
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"image-processor/internal/domain"
+	watermark_repo "image-processor/internal/repository/watermark"
+
+	"github.com/google/uuid"
+	"github.com/wb-go/wbf/zlog"
+)
+
+type WatermarkUsecase struct {
+	repo     watermarkRepository
+	fileRepo fileRepository
+	decoder  imageDecoder
+	logger   *zlog.Zerolog
+}
+
+func NewWatermarkUsecase(repo watermarkRepository, fileRepo fileRepository, decoder imageDecoder, logger *zlog.Zerolog) *WatermarkUsecase {
+	return &WatermarkUsecase{
+		repo:     repo,
+		fileRepo: fileRepo,
+		decoder:  decoder,
+		logger:   logger,
+	}
+}
+
+func (u *WatermarkUsecase) UploadWatermark(ctx context.Context, file io.Reader, filename string) (*domain.WatermarkAsset, error) {
+	data, err := io.ReadAll(io.LimitReader(file, domain.MaxWatermarkAssetSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watermark: %w", err)
+	}
+	if len(data) > domain.MaxWatermarkAssetSize {
+		return nil, ErrFileTooLarge
+	}
+	format, mimeType, ok := u.decoder.DetectFormat(data)
+	if !ok {
+		return nil, ErrInvalidFileFormat
+	}
+	img, _, err := u.decoder.Decode(data)
+	if err != nil {
+		u.logger.Warn().Err(err).Str("filename", filename).Msg("Failed to decode watermark")
+		return nil, ErrInvalidFileFormat
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() > domain.MaxWatermarkAssetPixel || bounds.Dy() > domain.MaxWatermarkAssetPixel {
+		return nil, ErrFileTooLarge
+	}
+	id := uuid.New().String()
+	asset := &domain.WatermarkAsset{
+		ID:       id,
+		Filename: filepath.Base(filename),
+		MimeType: mimeType,
+		Format:   format,
+		Width:    bounds.Dx(),
+		Height:   bounds.Dy(),
+		Size:     int64(len(data)),
+		Path:     domain.WatermarkAssetPath(id),
+	}
+	if err := u.fileRepo.SaveProcessed(ctx, asset.Path, bytes.NewReader(data), asset.Size, mimeType); err != nil {
+		u.logger.Error().Err(err).Str("watermark_id", id).Msg("Failed to save watermark to storage")
+		return nil, fmt.Errorf("%w: %v", ErrStorageError, err)
+	}
+	if err := u.repo.Create(ctx, asset); err != nil {
+		u.logger.Error().Err(err).Str("watermark_id", id).Msg("Failed to save watermark to DB")
+		if delErr := u.fileRepo.DeleteObject(ctx, asset.Path); delErr != nil {
+			u.logger.Error().Err(delErr).Str("path", asset.Path).Msg("Failed to cleanup watermark after DB error")
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	u.logger.Info().Str("watermark_id", id).Int("width", asset.Width).Int("height", asset.Height).Msg("Watermark uploaded")
+	return asset, nil
+}
+
+func (u *WatermarkUsecase) GetWatermark(ctx context.Context, id string) (*domain.WatermarkAsset, io.ReadCloser, error) {
+	asset, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		u.logger.Error().Err(err).Str("watermark_id", id).Msg("Failed to get watermark")
+		return nil, nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if asset == nil {
+		return nil, nil, ErrWatermarkNotFound
+	}
+	reader, err := u.fileRepo.GetObject(ctx, asset.Path)
+	if err != nil {
+		u.logger.Error().Err(err).Str("watermark_id", id).Str("path", asset.Path).Msg("Failed to get watermark from storage")
+		return nil, nil, fmt.Errorf("%w: %v", ErrStorageError, err)
+	}
+	return asset, reader, nil
+}
+
+func (u *WatermarkUsecase) ListWatermarks(ctx context.Context) ([]domain.WatermarkAsset, error) {
+	assets, err := u.repo.List(ctx)
+	if err != nil {
+		u.logger.Error().Err(err).Msg("Failed to list watermarks")
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	return assets, nil
+}
+
+func (u *WatermarkUsecase) DeleteWatermark(ctx context.Context, id string) error {
+	if err := u.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, watermark_repo.ErrWatermarkNotFound) {
+			return ErrWatermarkNotFound
+		}
+		u.logger.Error().Err(err).Str("watermark_id", id).Msg("Failed to delete watermark")
+		return fmt.Errorf("%w: %v", ErrDatabaseError, err)
+	}
+	if err := u.fileRepo.DeleteObject(ctx, domain.WatermarkAssetPath(id)); err != nil {
+		u.logger.Error().Err(err).Str("watermark_id", id).Msg("Failed to delete watermark from storage")
+	}
+	u.logger.Info().Str("watermark_id", id).Msg("Watermark deleted")
+	return nil
+}
@@ -53,7 +53,7 @@ func NewWorker(cfg *config.Config, logger *zlog.Zerolog) (*Worker, error) {
 	}
 	imageRepo := postgres_repo.NewImagesRepository(db, retries)
 	consumer := kafka_impl.NewConsumerClient(cfg)
-	processor := processor.NewImageProcessor(fileRepo, logger)
+	processor := processor.NewImageProcessor(fileRepo, imageRepo, cfg.MetadataPolicy(), cfg.Processing.ResampleKernel, logger)
 	concurrency := cfg.Worker.Concurrency
 	logger.Info().
 		Strs("brokers", cfg.Kafka.Brokers).
@@ -174,6 +174,12 @@ func (w *Worker) processMessage(ctx context.Context, msg *broker.Message) error
 		Int("operations", len(task.Operations)).
 		Int64("offset", msg.Offset).
 		Msg("Processing task started")
+	img, err := w.imageRepo.GetByID(ctx, task.ImageID)
+	if err != nil {
+		w.logger.Error().Err(err).Str("image_id", task.ImageID).Msg("Failed to load image")
+		return fmt.Errorf("failed to load image: %w", err)
+	}
+	task.OriginalPath = img.OriginalPath
 	reader, err := w.fileRepo.GetObject(ctx, task.OriginalPath)
 	if err != nil {
 		w.logger.Error().Err(err).Str("image_id", task.ImageID).Str("path", task.OriginalPath).Msg("Failed to get original image")
@@ -199,17 +205,31 @@ func (w *Worker) processMessage(ctx context.Context, msg *broker.Message) error
 		}
 		return fmt.Errorf("image processing failed: %w", err)
 	}
-	for operation, path := range result.ProcessedPaths {
+	for _, variant := range result.Variants {
 		processedImage := &domain.ProcessedImage{
-			ImageID:   task.ImageID,
-			Operation: domain.OperationType(operation),
-			Path:      path,
-			Status:    "completed",
-			Format:    task.Format,
-			CreatedAt: time.Now(),
+			ImageID:       task.ImageID,
+			Operation:     variant.Operation,
+			Parameters:    variant.Parameters,
+			Variant:       variant.Variant,
+			Steps:         variant.Steps,
+			CacheKey:      variant.CacheKey,
+			Preset:        task.Preset,
+			PresetVersion: task.PresetVersion,
+			CropBox:       variant.CropBox,
+			Path:          variant.Path,
+			Size:          variant.Size,
+			MimeType:      variant.MimeType,
+			Status:        "completed",
+			Format:        variant.Format,
+			CreatedAt:     time.Now(),
 		}
 		if err := w.imageRepo.SaveProcessedImage(ctx, processedImage); err != nil {
-			w.logger.Error().Err(err).Str("image_id", task.ImageID).Str("operation", operation).Msg("Failed to save processed image metadata")
+			w.logger.Error().Err(err).Str("image_id", task.ImageID).Str("operation", string(variant.Operation)).Str("variant", variant.Variant).Msg("Failed to save processed image metadata")
+		}
+	}
+	if result.Metadata != nil {
+		if err := w.imageRepo.UpdateMetadata(ctx, task.ImageID, *result.Metadata); err != nil {
+			w.logger.Error().Err(err).Str("image_id", task.ImageID).Msg("Failed to save image metadata")
 		}
 	}
 	if result.Status == domain.StatusCompleted {
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE processed_images ADD COLUMN IF NOT EXISTS variant VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE processed_images ADD COLUMN IF NOT EXISTS steps TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_processed_images_variant ON processed_images(image_id, variant);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_processed_images_variant;
+ALTER TABLE processed_images DROP COLUMN IF EXISTS steps;
+ALTER TABLE processed_images DROP COLUMN IF EXISTS variant;
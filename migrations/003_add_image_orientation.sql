@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE images ADD COLUMN IF NOT EXISTS orientation SMALLINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN IF EXISTS orientation;
@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE images ADD COLUMN IF NOT EXISTS width INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE images ADD COLUMN IF NOT EXISTS height INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE images ADD COLUMN IF NOT EXISTS color_model VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE images ADD COLUMN IF NOT EXISTS bit_depth SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE images ADD COLUMN IF NOT EXISTS frame_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE images ADD COLUMN IF NOT EXISTS exif JSONB;
+
+CREATE INDEX idx_images_exif ON images USING GIN (exif);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_images_exif;
+ALTER TABLE images DROP COLUMN IF EXISTS exif;
+ALTER TABLE images DROP COLUMN IF EXISTS frame_count;
+ALTER TABLE images DROP COLUMN IF EXISTS bit_depth;
+ALTER TABLE images DROP COLUMN IF EXISTS color_model;
+ALTER TABLE images DROP COLUMN IF EXISTS height;
+ALTER TABLE images DROP COLUMN IF EXISTS width;
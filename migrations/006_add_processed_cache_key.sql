@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE processed_images ADD COLUMN IF NOT EXISTS cache_key VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_processed_images_cache_key ON processed_images(image_id, cache_key) WHERE cache_key <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_processed_images_cache_key;
+ALTER TABLE processed_images DROP COLUMN IF EXISTS cache_key;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS presets (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    pipeline BOOLEAN NOT NULL DEFAULT FALSE,
+    operations JSONB NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE processed_images ADD COLUMN IF NOT EXISTS preset VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE processed_images ADD COLUMN IF NOT EXISTS preset_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_processed_images_preset ON processed_images(preset, preset_version);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_processed_images_preset;
+ALTER TABLE processed_images DROP COLUMN IF EXISTS preset_version;
+ALTER TABLE processed_images DROP COLUMN IF EXISTS preset;
+DROP TABLE IF EXISTS presets;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE processed_images ADD COLUMN IF NOT EXISTS crop_box JSONB;
+
+-- +goose Down
+ALTER TABLE processed_images DROP COLUMN IF EXISTS crop_box;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS fonts (
+    id UUID PRIMARY KEY,
+    filename VARCHAR(255) NOT NULL,
+    family VARCHAR(255) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    size BIGINT NOT NULL,
+    path VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS fonts;
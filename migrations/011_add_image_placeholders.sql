@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE images ADD COLUMN IF NOT EXISTS blurhash VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE images ADD COLUMN IF NOT EXISTS thumbhash VARCHAR(64) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN IF EXISTS thumbhash;
+ALTER TABLE images DROP COLUMN IF EXISTS blurhash;
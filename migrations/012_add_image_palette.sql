@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE images ADD COLUMN IF NOT EXISTS average_color VARCHAR(7) NOT NULL DEFAULT '';
+ALTER TABLE images ADD COLUMN IF NOT EXISTS palette JSONB;
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN IF EXISTS palette;
+ALTER TABLE images DROP COLUMN IF EXISTS average_color;